@@ -1,6 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/core"
 	"github.com/sirupsen/logrus"
 	_ "github.com/swaggo/files"
@@ -22,7 +30,29 @@ func main() {
 		logrus.Fatal("Failed to bootstrap app:", err)
 	}
 
-	if err := app.Router.Run(":8080"); err != nil {
-		logrus.Fatal("Failed to run server:", err)
+	// Listen before reporting ready, so readiness never passes before the
+	// port accepts connections.
+	listener, err := net.Listen("tcp", app.Server.Addr)
+	if err != nil {
+		logrus.Fatal("Failed to listen:", err)
 	}
+
+	go func() {
+		if err := app.Server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Fatal("Failed to run server:", err)
+		}
+	}()
+	app.Health.MarkReady()
+	logrus.Infof("Server listening on %s", listener.Addr())
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	// A second signal kills the process without waiting for the drain.
+	signal.Stop(quit)
+
+	logrus.Infof("Shutting down, draining requests and uploads for up to %s...", app.ShutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), app.ReadinessDelay+app.ShutdownTimeout)
+	defer cancel()
+	app.Shutdown(ctx)
 }
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
+)
+
+type AdminUseCase struct {
+	cfg *config.Config
+}
+
+func NewAdminUseCase(cfg *config.Config) *AdminUseCase {
+	return &AdminUseCase{
+		cfg: cfg,
+	}
+}
+
+// Config returns the configuration the service started with. Secrets are
+// redacted when it is loaded, so nothing here can leak them.
+func (a *AdminUseCase) Config() *dto.ConfigResponse {
+	settings := make([]dto.ConfigSetting, 0, len(a.cfg.Settings()))
+	for _, setting := range a.cfg.Settings() {
+		settings = append(settings, dto.ConfigSetting{
+			Key:    setting.Key,
+			Value:  setting.Value,
+			Source: setting.Source,
+		})
+	}
+
+	return &dto.ConfigResponse{
+		File:     a.cfg.File,
+		Settings: settings,
+	}
+}
@@ -13,13 +13,14 @@ import (
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/mail"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
 	base "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/entity"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/util"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
 	"github.com/google/uuid"
 	"github.com/markbates/goth"
-	"github.com/sirupsen/logrus"
 	"google.golang.org/api/idtoken"
 )
 
@@ -45,7 +46,7 @@ func (a *AuthUseCase) Register(req *dto.RegisterRequest, ctx context.Context) (*
 	existingUser, _ := a.repo.FindByEmail(ctx, req.Email)
 
 	if existingUser != nil {
-		logrus.Error("User already exists")
+		logging.FromContext(ctx).Error("User already exists")
 		return nil, errorEntity.ErrEmailAlreadyExists
 	}
 
@@ -59,121 +60,121 @@ func (a *AuthUseCase) Register(req *dto.RegisterRequest, ctx context.Context) (*
 	)
 
 	if err != nil {
-		logrus.Error("Failed to create new user")
+		logging.FromContext(ctx).Error("Failed to create new user")
 		return nil, err
 	}
 
 	err = a.repo.Create(ctx, newUser)
 	if err != nil {
-		logrus.Error("Failed to create new user in database")
+		logging.FromContext(ctx).Error("Failed to create new user in database")
 		return nil, err
 	}
 
-	logrus.Info("User created successfully")
+	logging.FromContext(ctx).Info("User created successfully")
 	return newUser, nil
 }
 
 func (a *AuthUseCase) Login(req *dto.LoginRequest, ctx context.Context) (*dto.LoginResponse, error) {
 	existingUser, err := a.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
-		logrus.Error("User not found")
+		logging.FromContext(ctx).Error("User not found")
 		return nil, errorEntity.ErrUserNotFound
 	}
 
 	if existingUser.EmailVerified == nil {
-		logrus.Error("Email not verified")
+		logging.FromContext(ctx).Error("Email not verified")
 		return nil, errorEntity.ErrEmailNotVerified
 	}
 
 	if !util.ComparePassword(existingUser.Password, req.Password, a.cfg.Server.Salt) {
-		logrus.Error("Invalid password")
+		logging.FromContext(ctx).Error("Invalid password")
 		return nil, errorEntity.ErrInvalidPassword
 	}
 
 	acc, refresh, refreshHash, err := a.jwt.GenerateToken(existingUser.UUID, existingUser.Email)
 	if err != nil {
-		logrus.Error("Failed to generate token")
+		logging.FromContext(ctx).Error("Failed to generate token")
 		return nil, err
 	}
 
-	accessExpire, _ := tm.ParseDuration(a.cfg.Jwt.AccessTokenExpire)
-	refreshExpire, _ := tm.ParseDuration(a.cfg.Jwt.RefreshTokenExpire)
+	accessExpire := a.cfg.Jwt.AccessTokenExpire
+	refreshExpire := a.cfg.Jwt.RefreshTokenExpire
 
 	jsonUser, err := json.Marshal(existingUser)
 	if err != nil {
-		logrus.Error("Failed to marshal user data")
+		logging.FromContext(ctx).Error("Failed to marshal user data")
 		return nil, err
 	}
 
 	err = a.redis.Set(fmt.Sprintf("user:%s", existingUser.UUID.String()), jsonUser, accessExpire)
 	if err != nil {
-		logrus.Error("Failed to set user in Redis:", err)
+		logging.FromContext(ctx).Error("Failed to set user in Redis:", err)
 		return nil, err
 	}
 
 	err = a.redis.Set(fmt.Sprintf("user_refresh:%s", existingUser.UUID.String()), refreshHash, refreshExpire)
 	if err != nil {
-		logrus.Error("Failed to set user_refresh in Redis:", err)
+		logging.FromContext(ctx).Error("Failed to set user_refresh in Redis:", err)
 		return nil, err
 	}
 
-	logrus.Info("User logged in successfully")
+	logging.FromContext(ctx).Info("User logged in successfully")
 	return &dto.LoginResponse{
 		AccessToken:  acc,
 		RefreshToken: refresh,
 	}, nil
 }
 
-func (a *AuthUseCase) Logout(req *dto.LogoutRequest, user *entity.User, accessToken string) error {
+func (a *AuthUseCase) Logout(req *dto.LogoutRequest, user *entity.User, accessToken string, ctx context.Context) error {
 	claims, err := a.jwt.ValidateToken(req.RefreshToken, a.cfg.Jwt.RefreshTokenSecret)
 	if err != nil {
-		logrus.Error("Invalid refresh token")
+		logging.FromContext(ctx).Error("Invalid refresh token")
 		return errorEntity.ErrInvalidToken
 	}
 
 	if claims.UUID != user.UUID {
-		logrus.Error("Invalid user")
+		logging.FromContext(ctx).Error("Invalid user")
 		return errorEntity.ErrInvalidUser
 	}
 
 	err = a.redis.Delete(fmt.Sprintf("user:%s", user.UUID))
 	if err != nil {
-		logrus.Error("Failed to delete access token from Redis")
+		logging.FromContext(ctx).Error("Failed to delete access token from Redis")
 		return err
 	}
 
 	err = a.redis.Delete(fmt.Sprintf("user_refresh:%s", user.UUID))
 	if err != nil {
-		logrus.Error("Failed to delete refresh token from Redis")
+		logging.FromContext(ctx).Error("Failed to delete refresh token from Redis")
 		return err
 	}
 
 	isBlacklisted, err := a.redis.Exists(fmt.Sprintf("blacklist:%s", req.RefreshToken))
 	if err != nil {
-		logrus.Error("Failed to check if token is blacklisted")
+		logging.FromContext(ctx).Error("Failed to check if token is blacklisted")
 		return err
 	}
 
 	if isBlacklisted {
-		logrus.Error("Token is already blacklisted")
+		logging.FromContext(ctx).Error("Token is already blacklisted")
 		return errorEntity.ErrTokenAlreadyBlacklisted
 	}
 
-	expireDuration, _ := tm.ParseDuration(a.cfg.Jwt.RefreshTokenExpire)
+	expireDuration := a.cfg.Jwt.RefreshTokenExpire
 	err = a.redis.Set(fmt.Sprintf("blacklist:%s", req.RefreshToken), "blacklisted", expireDuration)
 	if err != nil {
-		logrus.Error("Failed to set blacklist refresh token:", err)
+		logging.FromContext(ctx).Error("Failed to set blacklist refresh token:", err)
 		return err
 	}
 
-	expireDuration, _ = tm.ParseDuration(a.cfg.Jwt.AccessTokenExpire)
+	expireDuration = a.cfg.Jwt.AccessTokenExpire
 	err = a.redis.Set(fmt.Sprintf("blacklist:%s", accessToken), "blacklisted", expireDuration)
 	if err != nil {
-		logrus.Error("Failed to set blacklist access token:", err)
+		logging.FromContext(ctx).Error("Failed to set blacklist access token:", err)
 		return err
 	}
 
-	logrus.Info("User logged out successfully")
+	logging.FromContext(ctx).Info("User logged out successfully")
 
 	return nil
 }
@@ -181,49 +182,48 @@ func (a *AuthUseCase) Logout(req *dto.LogoutRequest, user *entity.User, accessTo
 func (a *AuthUseCase) RefreshToken(req *dto.RefreshTokenRequest, ctx context.Context) (*dto.RefreshTokenResponse, error) {
 	isBlacklisted, err := a.redis.Exists(fmt.Sprintf("blacklist:%s", req.RefreshToken))
 	if err != nil {
-		logrus.Error("Failed to check if token is blacklisted")
+		logging.FromContext(ctx).Error("Failed to check if token is blacklisted")
 		return nil, err
 	}
 
 	if isBlacklisted {
-		logrus.Error("Token is blacklisted")
+		logging.FromContext(ctx).Error("Token is blacklisted")
 		return nil, errorEntity.ErrTokenAlreadyBlacklisted
 	}
 
 	claims, err := a.jwt.ValidateToken(req.RefreshToken, a.cfg.Jwt.RefreshTokenSecret)
 	if err != nil {
-		logrus.Error("Invalid refresh token")
+		logging.FromContext(ctx).Error("Invalid refresh token")
 		return nil, errorEntity.ErrInvalidToken
 	}
 
 	user := &entity.User{Entity: base.Entity{UUID: claims.UUID}}
 	err = a.repo.Find(ctx, user)
 	if err != nil {
-		logrus.Error("Invalid user")
+		logging.FromContext(ctx).Error("Invalid user")
 		return nil, errorEntity.ErrInvalidUser
 	}
 
 	if claims.UUID != user.UUID {
-		logrus.Error("Invalid user")
+		logging.FromContext(ctx).Error("Invalid user")
 		return nil, errorEntity.ErrInvalidUser
 	}
 
 	hashRefresh, err := a.redis.Get(fmt.Sprintf("user_refresh:%s", user.UUID))
 	if err != nil {
-		logrus.Error("Failed to get refresh token from Redis")
+		logging.FromContext(ctx).Error("Failed to get refresh token from Redis")
 		return nil, err
 	}
 
 	err = a.jwt.CompareTokenHash(req.RefreshToken, hashRefresh)
 	if err != nil {
-		logrus.Error("Invalid refresh token")
+		logging.FromContext(ctx).Error("Invalid refresh token")
 		return nil, errorEntity.ErrInvalidToken
 	}
 
-	time, _ := tm.ParseDuration(a.cfg.Jwt.AccessTokenExpire)
-	token, err := a.jwt.GenerateSingleToken(claims.UUID, claims.Email, time, a.cfg.Jwt.AccessTokenSecret)
+	token, err := a.jwt.GenerateSingleToken(claims.UUID, claims.Email, a.cfg.Jwt.AccessTokenExpire, a.cfg.Jwt.AccessTokenSecret)
 	if err != nil {
-		logrus.Error("Failed to generate new access token")
+		logging.FromContext(ctx).Error("Failed to generate new access token")
 		return nil, err
 	}
 
@@ -233,7 +233,7 @@ func (a *AuthUseCase) RefreshToken(req *dto.RefreshTokenRequest, ctx context.Con
 func (a *AuthUseCase) SendOtp(body *dto.SendOtpRequest, ctx context.Context) error {
 	existingUser, err := a.repo.FindByEmail(ctx, body.Email)
 	if err != nil {
-		logrus.Error("User not found")
+		logging.FromContext(ctx).Error("User not found")
 		return errorEntity.ErrUserNotFound
 	}
 
@@ -241,20 +241,21 @@ func (a *AuthUseCase) SendOtp(body *dto.SendOtpRequest, ctx context.Context) err
 
 	count, err := a.redis.Incr(limitKey)
 	if err != nil {
-		logrus.WithError(err).Error("Failed to increment OTP limit key")
+		logging.FromContext(ctx).WithError(err).Error("Failed to increment OTP limit key")
 		return fmt.Errorf("internal error")
 	}
 
 	if count == 1 {
 		err = a.redis.Expire(limitKey, 5*tm.Minute)
 		if err != nil {
-			logrus.WithError(err).Error("Failed to set expiry on OTP limit key")
+			logging.FromContext(ctx).WithError(err).Error("Failed to set expiry on OTP limit key")
 			return fmt.Errorf("internal error")
 		}
 	}
 
 	if count > 5 {
-		logrus.Warn("OTP request limit exceeded")
+		logging.FromContext(ctx).Warn("OTP request limit exceeded")
+		metrics.RateLimitRejections.WithLabelValues("otp").Inc()
 		return errorEntity.ErrLimitExceeded
 	}
 
@@ -263,73 +264,73 @@ func (a *AuthUseCase) SendOtp(body *dto.SendOtpRequest, ctx context.Context) err
 	go func(email, otp string) {
 		err := a.mail.SendMail(email, "OTP Verification", fmt.Sprintf("Your OTP is: %s, This will expired after 5 minutes", otp))
 		if err != nil {
-			logrus.WithError(err).Error("Failed to send OTP email")
+			logging.FromContext(ctx).WithError(err).Error("Failed to send OTP email")
 		}
 	}(existingUser.Email, otp)
 
 	err = a.redis.Set(fmt.Sprintf("otp:%s", existingUser.UUID), otp, 5*tm.Minute)
 	if err != nil {
-		logrus.Error("Failed to set OTP in Redis")
+		logging.FromContext(ctx).Error("Failed to set OTP in Redis")
 		return err
 	}
 
-	logrus.Info("OTP sent successfully")
+	logging.FromContext(ctx).Info("OTP sent successfully")
 	return nil
 }
 
 func (a *AuthUseCase) VerifyEmail(req *dto.VerifyEmailRequest, ctx context.Context) error {
 	existingUser, err := a.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
-		logrus.Error("User not found")
+		logging.FromContext(ctx).Error("User not found")
 		return errorEntity.ErrUserNotFound
 	}
 
 	otp, err := a.redis.Get(fmt.Sprintf("otp:%s", existingUser.UUID))
 	if err != nil {
-		logrus.Error("Failed to get OTP from Redis")
+		logging.FromContext(ctx).Error("Failed to get OTP from Redis")
 		return errorEntity.ErrOtpNotFound
 	}
 
 	if otp != req.Otp {
-		logrus.Error("Invalid OTP")
+		logging.FromContext(ctx).Error("Invalid OTP")
 		return errorEntity.ErrInvalidOtp
 	}
 
 	err = a.repo.UpdateEmailVerified(ctx, existingUser.UUID)
 	if err != nil {
-		logrus.Error("Failed to update email verified status")
+		logging.FromContext(ctx).Error("Failed to update email verified status")
 		return err
 	}
 
 	if err := a.redis.Delete(fmt.Sprintf("otp:%s", existingUser.UUID)); err != nil {
-		logrus.Error("Failed to delete otp key:", err)
+		logging.FromContext(ctx).Error("Failed to delete otp key:", err)
 		return err
 	}
 
 	if err := a.redis.Delete(fmt.Sprintf("otp_limit:%s", existingUser.UUID)); err != nil {
-		logrus.Error("Failed to delete otp_limit key:", err)
+		logging.FromContext(ctx).Error("Failed to delete otp_limit key:", err)
 		return err
 	}
 
-	logrus.Info("Email verified successfully")
+	logging.FromContext(ctx).Info("Email verified successfully")
 	return nil
 }
 
 func (a *AuthUseCase) ForgotPassword(req *dto.ForgotPasswordRequest, ctx context.Context) error {
 	existingUser, err := a.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
-		logrus.Error("User not found")
+		logging.FromContext(ctx).Error("User not found")
 		return errorEntity.ErrUserNotFound
 	}
 
 	otp, err := a.redis.Get(fmt.Sprintf("otp:%s", existingUser.UUID))
 	if err != nil {
-		logrus.Error("Failed to get OTP from Redis")
+		logging.FromContext(ctx).Error("Failed to get OTP from Redis")
 		return errorEntity.ErrOtpNotFound
 	}
 
 	if otp != req.Otp {
-		logrus.Error("Invalid OTP")
+		logging.FromContext(ctx).Error("Invalid OTP")
 		return errorEntity.ErrInvalidOtp
 	}
 
@@ -350,18 +351,18 @@ func (a *AuthUseCase) ForgotPassword(req *dto.ForgotPasswordRequest, ctx context
 		}
 
 		if err := a.repo.Update(ctx, existingUser); err != nil {
-			logrus.Error("Failed to update user password")
+			logging.FromContext(ctx).Error("Failed to update user password")
 			errHandler.SetError(err)
 		}
 		jsonUser, err := json.Marshal(existingUser)
 		if err != nil {
-			logrus.Error("Failed to marshal user data")
+			logging.FromContext(ctx).Error("Failed to marshal user data")
 			errHandler.SetError(err)
 			return
 		}
 
 		if err := a.redis.Set(fmt.Sprintf("user:%s", existingUser.UUID), jsonUser, 0); err != nil {
-			logrus.Error("Failed to set user data in Redis:", err)
+			logging.FromContext(ctx).Error("Failed to set user data in Redis:", err)
 			errHandler.SetError(err)
 		}
 	}()
@@ -369,12 +370,12 @@ func (a *AuthUseCase) ForgotPassword(req *dto.ForgotPasswordRequest, ctx context
 	go func() {
 		defer wg.Done()
 		if err := a.redis.Delete(fmt.Sprintf("otp:%s", existingUser.UUID)); err != nil {
-			logrus.Error("Failed to delete otp key from Redis:", err)
+			logging.FromContext(ctx).Error("Failed to delete otp key from Redis:", err)
 			errHandler.SetError(err)
 			return
 		}
 		if err := a.redis.Delete(fmt.Sprintf("otp_limit:%s", existingUser.UUID)); err != nil {
-			logrus.Error("Failed to delete otp_limit key from Redis:", err)
+			logging.FromContext(ctx).Error("Failed to delete otp_limit key from Redis:", err)
 			errHandler.SetError(err)
 		}
 	}()
@@ -385,7 +386,7 @@ func (a *AuthUseCase) ForgotPassword(req *dto.ForgotPasswordRequest, ctx context
 		return err
 	}
 
-	logrus.Info("Password updated successfully")
+	logging.FromContext(ctx).Info("Password updated successfully")
 	return nil
 }
 
@@ -405,28 +406,28 @@ func (a *AuthUseCase) GoogleLogin(g *goth.User, ctx context.Context) (*dto.Login
 		}
 		err := a.repo.Create(ctx, user)
 		if err != nil {
-			logrus.Error("Failed to create new user from Google login:", err)
+			logging.FromContext(ctx).Error("Failed to create new user from Google login:", err)
 			return nil, err
 		}
 
-		logrus.Info("New user created from Google login")
+		logging.FromContext(ctx).Info("New user created from Google login")
 	} else if user.EmailVerified == nil {
 		err := a.repo.UpdateEmailVerified(ctx, user.UUID)
 		if err != nil {
-			logrus.Error("Failed to update email verified status:", err)
+			logging.FromContext(ctx).Error("Failed to update email verified status:", err)
 			return nil, errorEntity.ErrEmailNotVerified
 		}
 		user.EmailVerified = &now
 	}
 
 	if user.UUID == uuid.Nil {
-		logrus.Error("User UUID is empty")
+		logging.FromContext(ctx).Error("User UUID is empty")
 		return nil, errorEntity.ErrUserNotFound
 	}
 
 	acc, refresh, refreshHash, err := a.jwt.GenerateToken(user.UUID, user.Email)
 	if err != nil {
-		logrus.Error("Failed to generate JWT token:", err)
+		logging.FromContext(ctx).Error("Failed to generate JWT token:", err)
 		return nil, err
 	}
 
@@ -435,27 +436,27 @@ func (a *AuthUseCase) GoogleLogin(g *goth.User, ctx context.Context) (*dto.Login
 		RefreshToken: refresh,
 	}
 
-	accessExpire, _ := tm.ParseDuration(a.cfg.Jwt.AccessTokenExpire)
-	refreshExpire, _ := tm.ParseDuration(a.cfg.Jwt.RefreshTokenExpire)
+	accessExpire := a.cfg.Jwt.AccessTokenExpire
+	refreshExpire := a.cfg.Jwt.RefreshTokenExpire
 	jsonUser, err := json.Marshal(user)
 	if err != nil {
-		logrus.Error("Failed to marshal user data")
+		logging.FromContext(ctx).Error("Failed to marshal user data")
 		return nil, err
 	}
 
 	err = a.redis.Set(fmt.Sprintf("user:%s", user.UUID.String()), jsonUser, accessExpire)
 	if err != nil {
-		logrus.Error("Failed to set user data in Redis:", err)
+		logging.FromContext(ctx).Error("Failed to set user data in Redis:", err)
 		return nil, err
 	}
-	logrus.Info("Set user data in Redis")
+	logging.FromContext(ctx).Info("Set user data in Redis")
 
 	err = a.redis.Set(fmt.Sprintf("user_refresh:%s", user.UUID.String()), refreshHash, refreshExpire)
 	if err != nil {
-		logrus.Error("Failed to set refresh token in Redis:", err)
+		logging.FromContext(ctx).Error("Failed to set refresh token in Redis:", err)
 		return nil, err
 	}
-	logrus.Info("Set refresh token in Redis")
+	logging.FromContext(ctx).Info("Set refresh token in Redis")
 
 	return loginReq, nil
 }
@@ -463,12 +464,12 @@ func (a *AuthUseCase) GoogleLogin(g *goth.User, ctx context.Context) (*dto.Login
 func (a *AuthUseCase) SetPassword(req *dto.SetPasswordRequest, e *entity.User, ctx context.Context) error {
 	existingUser, err := a.repo.FindByEmail(ctx, e.Email)
 	if err != nil {
-		logrus.Error("User not found")
+		logging.FromContext(ctx).Error("User not found")
 		return errorEntity.ErrUserNotFound
 	}
 
 	if existingUser.Password != "" {
-		logrus.Error("User already has a password")
+		logging.FromContext(ctx).Error("User already has a password")
 		return errorEntity.ErrUserAlreadyHasPassword
 	}
 
@@ -478,32 +479,31 @@ func (a *AuthUseCase) SetPassword(req *dto.SetPasswordRequest, e *entity.User, c
 
 	err = a.repo.Update(ctx, existingUser)
 	if err != nil {
-		logrus.Error("Failed to update user password")
+		logging.FromContext(ctx).Error("Failed to update user password")
 		return err
 	}
 
 	jsonUser, _ := json.Marshal(existingUser)
 	err = a.redis.Set(fmt.Sprintf("user:%s", existingUser.UUID), jsonUser, 0)
 	if err != nil {
-		logrus.Error("Failed to set user data in Redis:", err)
+		logging.FromContext(ctx).Error("Failed to set user data in Redis:", err)
 		return err
 	}
-	logrus.Info("Password updated successfully")
+	logging.FromContext(ctx).Info("Password updated successfully")
 	return nil
 }
 
-func (a *AuthUseCase) GenerateApiKey(e *entity.User) (*dto.ApiKeyResponse, error) {
+func (a *AuthUseCase) GenerateApiKey(e *entity.User, ctx context.Context) (*dto.ApiKeyResponse, error) {
 	key, err := util.GenerateAPIKey(40)
 	if err != nil {
-		logrus.Error("Failed to generate API key:", err)
+		logging.FromContext(ctx).Error("Failed to generate API key:", err)
 		return nil, err
 	}
 
-	expire, _ := tm.ParseDuration(a.cfg.Server.ExpireKey)
 	redisKey := fmt.Sprintf("api_key:%s", key)
-	err = a.redis.Set(redisKey, e.UUID.String(), expire)
+	err = a.redis.Set(redisKey, e.UUID.String(), a.cfg.Server.ExpireKey)
 	if err != nil {
-		logrus.Error("Failed to set API key in Redis:", err)
+		logging.FromContext(ctx).Error("Failed to set API key in Redis:", err)
 		return nil, err
 	}
 
@@ -515,7 +515,7 @@ func (a *AuthUseCase) GoogleVerify(ctx context.Context, googleIdToken string) (*
 
 	payload, err := idtoken.Validate(ctx, googleIdToken, googleClientID)
 	if err != nil {
-		logrus.WithError(err).Error("Failed to validate Google ID token")
+		logging.FromContext(ctx).WithError(err).Error("Failed to validate Google ID token")
 		return nil, errorEntity.ErrInvalidToken
 	}
 
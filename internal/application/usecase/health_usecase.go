@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
+)
+
+const (
+	HealthStarting = "starting"
+	HealthReady    = "ready"
+	HealthDraining = "draining"
+)
+
+const (
+	componentUp   = "up"
+	componentDown = "down"
+)
+
+// healthCheckTimeout bounds each dependency check, so one hung dependency
+// cannot hold the probe past the orchestrator's timeout.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck probes one dependency. Details, if any, are reported alongside
+// its status.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) (details any, err error)
+}
+
+// HealthUseCase tracks whether the service should receive traffic. It starts
+// out "starting", becomes "ready" once the server is listening and turns
+// "draining" when shutdown begins.
+type HealthUseCase struct {
+	checks []HealthCheck
+	state  atomic.Value
+}
+
+func NewHealthUseCase(checks ...HealthCheck) *HealthUseCase {
+	h := &HealthUseCase{checks: checks}
+	h.state.Store(HealthStarting)
+	return h
+}
+
+func (h *HealthUseCase) MarkReady() {
+	h.state.Store(HealthReady)
+}
+
+func (h *HealthUseCase) MarkDraining() {
+	h.state.Store(HealthDraining)
+}
+
+// Readiness runs every check concurrently. The service is ready only when it
+// is in the ready state and every component is up.
+func (h *HealthUseCase) Readiness(ctx context.Context) (*dto.ReadinessResponse, bool) {
+	state := h.state.Load().(string)
+	res := &dto.ReadinessResponse{
+		Status:     state,
+		Components: make(map[string]dto.ComponentHealth, len(h.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ready := state == HealthReady
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			component := runHealthCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			res.Components[check.Name] = component
+			if component.Status != componentUp {
+				ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	return res, ready
+}
+
+func runHealthCheck(ctx context.Context, check HealthCheck) dto.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := check.Check(ctx)
+	component := dto.ComponentHealth{
+		Status:    componentUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Details:   details,
+	}
+	if err != nil {
+		component.Status = componentDown
+		component.Error = err.Error()
+	}
+	return component
+}
@@ -1,53 +1,588 @@
 package usecase
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/redis"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/storage"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/imaging"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	rodService "github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/rod"
-	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/util"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
-	"github.com/cloudinary/cloudinary-go/v2"
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/go-rod/rod"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
-	"io"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
 type PageCaptureUseCase struct {
 	repo            repository.PageCaptureRepository
 	redis           redis.Service
-	cloud           *cloudinary.Cloudinary
+	storage         storage.Service
+	uploads         *UploadUseCase
 	cfg             *config.Config
 	browserInstance *rod.Browser
 }
 
-func NewPageCaptureUseCase(repo repository.PageCaptureRepository, redis redis.Service, cfg *config.Config, cloud *cloudinary.Cloudinary, browser *rod.Browser) *PageCaptureUseCase {
+func NewPageCaptureUseCase(repo repository.PageCaptureRepository, redis redis.Service, cfg *config.Config, storage storage.Service, uploads *UploadUseCase, browser *rod.Browser) *PageCaptureUseCase {
 	return &PageCaptureUseCase{
 		repo:            repo,
 		redis:           redis,
-		cloud:           cloud,
+		storage:         storage,
+		uploads:         uploads,
 		cfg:             cfg,
 		browserInstance: browser,
 	}
 }
 
 func (c *PageCaptureUseCase) PageCapture(body *dto.PageCaptureRequest, key string, ctx context.Context) (*dto.PageCaptureResponse, error) {
+	ctx, span := tracing.Start(ctx, "PageCaptureUseCase.PageCapture", trace.WithAttributes(
+		attribute.Int("capture.viewports", len(body.Viewports)),
+	))
+	data, err := c.pageCapture(body, key, ctx)
+	if err == nil {
+		span.SetAttributes(attribute.Bool("capture.cache_hit", data.CacheHit))
+	}
+	tracing.End(span, err)
+	return data, err
+}
+
+func (c *PageCaptureUseCase) pageCapture(body *dto.PageCaptureRequest, key string, ctx context.Context) (*dto.PageCaptureResponse, error) {
+	user, err := c.userByApiKey(key, ctx)
+	if err != nil {
+		return nil, err
+	}
+	logging.AddFields(ctx, logrus.Fields{"user_id": user.UUID})
+
+	if len(body.Viewports) > 0 {
+		return c.pageCaptureViewports(body, user, ctx)
+	}
+
+	// A cached entry holds only the image and metadata, so captures with
+	// artifacts always render to return the same thing a fresh one would.
+	var cacheKey string
+	if body.CacheTtl > 0 && !body.HasArtifacts() {
+		hash, err := body.CacheKey()
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to build capture cache key: ", err)
+			return nil, err
+		}
+		cacheKey = fmt.Sprintf("capture_cache:%s:%s", user.UUID, hash)
+
+		// Debug captures always hit the browser, since their point is to
+		// record what happens during this load.
+		if !body.ForceRefresh && !body.Debug && !body.Trace {
+			if cached, err := c.redis.Get(cacheKey); err == nil && cached != "" {
+				var result rodService.CaptureResult
+				if err := json.Unmarshal([]byte(cached), &result); err == nil {
+					logging.FromContext(ctx).Info("capture served from cache")
+					return &dto.PageCaptureResponse{
+						Filename:    "screenshot.png",
+						ContentType: "image/png",
+						Content:     result.Image,
+						CacheHit:    true,
+						Metadata:    result.Metadata,
+					}, nil
+				}
+				logging.FromContext(ctx).Warn("failed to decode cached capture: ", err)
+			}
+		}
+	}
+
+	req, err := c.screenshotOptions(body, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.capture(body, *req, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheKey != "" {
+		if cached, err := json.Marshal(rodService.CaptureResult{Image: result.Image, Metadata: result.Metadata}); err != nil {
+			logging.FromContext(ctx).Warn("failed to encode capture for cache: ", err)
+		} else if err := c.redis.Set(cacheKey, cached, time.Duration(body.CacheTtl)*time.Second); err != nil {
+			logging.FromContext(ctx).Warn("failed to cache capture: ", err)
+		}
+	}
+
+	pageCapture := dto.ConvertRequestToEntity(*body, user.UUID)
+	if err := c.enqueueCapture(pageCapture, result, ctx); err != nil {
+		return nil, err
+	}
+
+	return &dto.PageCaptureResponse{
+		Filename:    "screenshot.png",
+		ContentType: "image/png",
+		Content:     result.Image,
+		CaptureID:   pageCapture.UUID,
+		Metadata:    result.Metadata,
+	}, nil
+}
+
+// screenshotOptions converts the request for the browser. The configured
+// proxy names are only logged, never returned to the caller.
+func (c *PageCaptureUseCase) screenshotOptions(body *dto.PageCaptureRequest, ctx context.Context) (*rodService.ScreenshotOptions, error) {
+	req, err := dto.ConvertToScreenshotOptions(body)
+	if err != nil {
+		log := logging.FromContext(ctx)
+		if errors.Is(err, errorEntity.ErrUnknownProxy) {
+			log = log.WithFields(logrus.Fields{"proxy": body.Proxy, "available_proxies": rodService.ProxyNames()})
+		}
+		log.Error("failed to prepare capture: ", err)
+		return nil, fmt.Errorf("%w: %v", errorEntity.ErrInvalidRequest, err)
+	}
+	return req, nil
+}
+
+// pageCaptureViewports navigates once per viewport with the shared options.
+// Every capture gets its own history row tagged with a common batch ID; the
+// response is a ZIP of the images, a contact sheet, or just the batch listing.
+// Batches are not cached.
+func (c *PageCaptureUseCase) pageCaptureViewports(body *dto.PageCaptureRequest, user *entity.User, ctx context.Context) (*dto.PageCaptureResponse, error) {
+	req, err := c.screenshotOptions(body, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output := body.Output
+	if output == "" {
+		output = dto.PageCaptureOutputZip
+	}
+
+	batchID := uuid.New()
+	batch := &dto.PageCaptureBatchResponse{BatchID: batchID}
+
+	var archive bytes.Buffer
+	zipWriter := zip.NewWriter(&archive)
+	var sheet []imaging.SheetImage
+	var metadata rodService.PageMetadata
+
+	for i, viewport := range body.Viewports {
+		name := viewport.ViewportName()
+
+		opt := *req
+		opt.Width = viewport.Width
+		opt.Height = viewport.Height
+		opt.IsMobile = viewport.IsMobile
+
+		logging.FromContext(ctx).Infof("Capturing viewport %d (%s)", i, name)
+		result, err := c.capture(body, opt, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("viewport %d (%s): %w", i, name, err)
+		}
+
+		pageCapture := dto.ConvertRequestToEntity(*body, user.UUID)
+		pageCapture.Width = &viewport.Width
+		pageCapture.Height = &viewport.Height
+		pageCapture.IsMobile = viewport.IsMobile
+		pageCapture.BatchID = &batchID
+		if err := c.enqueueCapture(pageCapture, result, ctx); err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			metadata = result.Metadata
+		}
+
+		batch.Captures = append(batch.Captures, dto.PageCaptureBatchItem{
+			CaptureID: pageCapture.UUID,
+			Name:      name,
+			Width:     viewport.Width,
+			Height:    viewport.Height,
+			IsMobile:  viewport.IsMobile,
+		})
+
+		switch output {
+		case dto.PageCaptureOutputZip:
+			file, err := zipWriter.Create(fmt.Sprintf("%02d-%s.png", i+1, safeFilename(name)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to add viewport to zip: %w", err)
+			}
+			if _, err := file.Write(result.Image); err != nil {
+				return nil, fmt.Errorf("failed to add viewport to zip: %w", err)
+			}
+		case dto.PageCaptureOutputSheet:
+			sheet = append(sheet, imaging.SheetImage{Label: name, Data: result.Image})
+		}
+	}
+
+	res := &dto.PageCaptureResponse{
+		Metadata: metadata,
+		Batch:    batch,
+	}
+
+	switch output {
+	case dto.PageCaptureOutputZip:
+		if err := zipWriter.Close(); err != nil {
+			return nil, fmt.Errorf("failed to write zip: %w", err)
+		}
+		res.Filename = "screenshots.zip"
+		res.ContentType = "application/zip"
+		res.Content = archive.Bytes()
+	case dto.PageCaptureOutputSheet:
+		data, err := imaging.ContactSheet(sheet)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to compose contact sheet: ", err)
+			return nil, err
+		}
+		res.Filename = "contact-sheet.png"
+		res.ContentType = "image/png"
+		res.Content = data
+	}
+
+	return res, nil
+}
+
+// capture renders the page and applies the request's transform. Thumbnails
+// are returned as artifacts.
+func (c *PageCaptureUseCase) capture(body *dto.PageCaptureRequest, opt rodService.ScreenshotOptions, ctx context.Context) (*rodService.CaptureResult, error) {
+	result, err := rodService.CaptureScreenshot(ctx, c.browserInstance, opt)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to capture screenshot: ", err)
+		var actionErr *rodService.ActionError
+		if errors.As(err, &actionErr) {
+			return nil, fmt.Errorf("%w: %v", errorEntity.ErrCaptureAction, actionErr)
+		}
+		return nil, err
+	}
+
+	if body.Transform != nil {
+		transformed, err := imaging.Transform(result.Image, dto.ConvertToTransformOptions(body.Transform))
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to transform screenshot: ", err)
+			if errors.Is(err, imaging.ErrEmptyCrop) {
+				return nil, fmt.Errorf("%w: %v", errorEntity.ErrInvalidRequest, err)
+			}
+			return nil, err
+		}
+
+		result.Image = transformed.Image
+		for _, thumb := range transformed.Thumbnails {
+			result.Artifacts = append(result.Artifacts, rodService.Artifact{
+				Kind:        artifactThumbnail,
+				Filename:    fmt.Sprintf("thumbnail-%d.png", thumb.Width),
+				ContentType: "image/png",
+				Data:        thumb.Data,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// enqueueCapture records the history row with its metadata and queues the
+// image and artifacts for upload.
+func (c *PageCaptureUseCase) enqueueCapture(pageCapture *entity.PageCapture, result *rodService.CaptureResult, ctx context.Context) error {
+	dto.ApplyMetadata(pageCapture, result.Metadata)
+
+	artifacts := make([]PendingArtifact, 0, len(result.Artifacts))
+	for _, artifact := range result.Artifacts {
+		artifacts = append(artifacts, PendingArtifact{
+			Artifact: entity.NewCaptureArtifact(pageCapture.UUID, artifact.Kind, artifact.Filename, artifact.ContentType, len(artifact.Data)),
+			Data:     artifact.Data,
+		})
+	}
+
+	return c.uploads.Enqueue(context.WithoutCancel(ctx), pageCapture, result.Image, "image/png", artifacts...)
+}
+
+func (c *PageCaptureUseCase) PageRecording(body *dto.PageRecordingRequest, key string, ctx context.Context) (*dto.PageCaptureResponse, error) {
+	user, err := c.userByApiKey(key, ctx)
+	if err != nil {
+		return nil, err
+	}
+	logging.AddFields(ctx, logrus.Fields{"user_id": user.UUID})
+
+	req := dto.ConvertToRecordingOptions(body)
+	result, err := rodService.RecordPage(ctx, c.browserInstance, *req)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to record page: ", err)
+		return nil, err
+	}
+
+	pageCapture := dto.ConvertRecordingRequestToEntity(*body, user.UUID)
+	dto.ApplyMetadata(pageCapture, result.Metadata)
+	if err := c.uploads.Enqueue(context.WithoutCancel(ctx), pageCapture, result.Data, result.ContentType); err != nil {
+		return nil, err
+	}
+
+	return &dto.PageCaptureResponse{
+		Filename:    "recording.gif",
+		ContentType: result.ContentType,
+		Content:     result.Data,
+		CaptureID:   pageCapture.UUID,
+		Metadata:    result.Metadata,
+	}, nil
+}
+
+func (c *PageCaptureUseCase) GetPageCapture(e *entity.User, query *dto.PageCaptureQuery, ctx context.Context) (*dto.PagesCaptureResponse, error) {
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errorEntity.ErrInvalidRequest, err)
+	}
+
+	data, err := c.repo.GetPageCaptureByUserID(ctx, e.UUID.String(), query)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to get page captures: ", err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (c *PageCaptureUseCase) GetPageCaptureDetail(e *entity.User, id string, ctx context.Context) (*entity.PageCapture, error) {
+	capture, err := c.findPageCapture(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if capture.UserID != e.UUID {
+		logging.FromContext(ctx).Warn("page capture does not belong to user")
+		return nil, errorEntity.ErrDataNotFound
+	}
+
+	artifacts, err := c.repo.FindArtifactsByCaptureIDs(ctx, []uuid.UUID{capture.UUID})
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to find page capture artifacts: ", err)
+		return nil, err
+	}
+	capture.Artifacts = artifacts
+
+	return capture, nil
+}
+
+func (c *PageCaptureUseCase) GetPageCaptureArtifact(e *entity.User, id string, artifactID string, ctx context.Context) (*dto.PageCaptureFile, error) {
+	capture, err := c.findPageCapture(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if capture.UserID != e.UUID {
+		logging.FromContext(ctx).Warn("page capture does not belong to user")
+		return nil, errorEntity.ErrDataNotFound
+	}
+
+	parsedArtifactID, err := uuid.Parse(artifactID)
+	if err != nil {
+		return nil, errorEntity.ErrInvalidRequest
+	}
+
+	artifact, err := c.repo.FindArtifact(ctx, capture.UUID, parsedArtifactID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorEntity.ErrDataNotFound
+		}
+		logging.FromContext(ctx).Error("failed to find page capture artifact: ", err)
+		return nil, err
+	}
+
+	if artifact.Status != entity.PageCaptureStatusStored {
+		return nil, errorEntity.ErrCaptureNotStored
+	}
+
+	content, err := c.storage.Open(ctx, artifact.PublicId, artifact.ContentType)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to open stored page capture artifact: ", err)
+		return nil, err
+	}
+
+	return &dto.PageCaptureFile{
+		Filename:    fmt.Sprintf("%s-%s", capture.UUID, artifact.Filename),
+		ContentType: artifact.ContentType,
+		Content:     content,
+	}, nil
+}
+
+func (c *PageCaptureUseCase) GetPageCaptureImage(e *entity.User, id string, ctx context.Context) (*dto.PageCaptureFile, error) {
+	capture, err := c.findPageCapture(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if capture.UserID != e.UUID {
+		logging.FromContext(ctx).Warn("page capture does not belong to user")
+		return nil, errorEntity.ErrDataNotFound
+	}
+
+	return c.openPageCapture(capture, ctx)
+}
+
+// CreateSignedUrl links to the capture under PUBLIC_URL, which config
+// requires along with SIGNED_URL_SECRET, so links never depend on the
+// request's Host header.
+func (c *PageCaptureUseCase) CreateSignedUrl(e *entity.User, id string, expiresIn int, ctx context.Context) (*dto.SignedUrlResponse, error) {
+	if c.cfg.Server.SignedUrlSecret == "" {
+		logging.FromContext(ctx).Error("SIGNED_URL_SECRET is not set")
+		return nil, errorEntity.ErrSigningNotConfigured
+	}
+
+	capture, err := c.findPageCapture(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if capture.UserID != e.UUID {
+		logging.FromContext(ctx).Warn("page capture does not belong to user")
+		return nil, errorEntity.ErrDataNotFound
+	}
+
+	baseUrl := strings.TrimSuffix(c.cfg.Server.PublicUrl, "/")
+
+	expiresAt := time.Now().Add(time.Duration(expiresIn) * time.Second).UTC().Truncate(time.Second)
+	signature := c.signPageCapture(capture.UUID.String(), expiresAt.Unix())
+
+	return &dto.SignedUrlResponse{
+		Url:       fmt.Sprintf("%s/page-capture/%s/shared?expires=%d&signature=%s", baseUrl, capture.UUID, expiresAt.Unix(), signature),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (c *PageCaptureUseCase) GetSharedPageCaptureImage(id string, expires int64, signature string, ctx context.Context) (*dto.PageCaptureFile, error) {
+	if c.cfg.Server.SignedUrlSecret == "" {
+		logging.FromContext(ctx).Error("SIGNED_URL_SECRET is not set")
+		return nil, errorEntity.ErrSigningNotConfigured
+	}
+
+	expected := c.signPageCapture(id, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		logging.FromContext(ctx).Warn("invalid page capture signature")
+		return nil, errorEntity.ErrInvalidSignature
+	}
+
+	if time.Now().Unix() > expires {
+		logging.FromContext(ctx).Warn("page capture signature expired")
+		return nil, errorEntity.ErrSignatureExpired
+	}
+
+	capture, err := c.findPageCapture(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.openPageCapture(capture, ctx)
+}
+
+func (c *PageCaptureUseCase) DeletePageCapture(e *entity.User, id string, ctx context.Context) error {
+	capture, err := c.findPageCapture(id, ctx)
+	if err != nil {
+		return err
+	}
+
+	if capture.UserID != e.UUID {
+		logging.FromContext(ctx).Warn("page capture does not belong to user")
+		return errorEntity.ErrDataNotFound
+	}
+
+	if _, err := removePageCaptures(ctx, c.repo, c.storage, []entity.PageCapture{*capture}); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("page capture deleted successfully")
+	return nil
+}
+
+func (c *PageCaptureUseCase) DeletePageCaptures(e *entity.User, body *dto.DeletePageCapturesRequest, ctx context.Context) (*dto.DeletePageCapturesResponse, error) {
+	ids := make([]uuid.UUID, 0, len(body.Ids))
+	for _, id := range body.Ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, errorEntity.ErrInvalidRequest
+		}
+		ids = append(ids, parsed)
+	}
+
+	captures, err := c.repo.FindByUserIDAndUUIDs(ctx, e.UUID, ids)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to find page captures: ", err)
+		return nil, err
+	}
+
+	deleted, err := removePageCaptures(ctx, c.repo, c.storage, captures)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Infof("%d page captures deleted successfully", deleted)
+	return &dto.DeletePageCapturesResponse{Deleted: deleted}, nil
+}
+
+// EnforceRetention removes captures that fall outside each user's retention
+// policy. It processes at most retentionBatchSize captures per rule and user,
+// so a large backlog is worked off over several sweeps.
+func (c *PageCaptureUseCase) EnforceRetention(ctx context.Context) error {
+	users, err := c.repo.GetUsersWithRetention(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to get users with retention policy: ", err)
+		return err
+	}
+
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var expired []entity.PageCapture
+
+		if user.RetentionDays != nil {
+			before := time.Now().AddDate(0, 0, -*user.RetentionDays)
+			captures, err := c.repo.FindCreatedBefore(ctx, user.UUID, before, retentionBatchSize)
+			if err != nil {
+				logging.FromContext(ctx).WithField("user_id", user.UUID).Error("failed to find expired page captures: ", err)
+				continue
+			}
+			expired = append(expired, captures...)
+		}
+
+		if user.RetentionMaxCaptures != nil {
+			captures, err := c.repo.FindBeyondLatest(ctx, user.UUID, *user.RetentionMaxCaptures, retentionBatchSize)
+			if err != nil {
+				logging.FromContext(ctx).WithField("user_id", user.UUID).Error("failed to find surplus page captures: ", err)
+				continue
+			}
+			expired = append(expired, captures...)
+		}
+
+		deleted, err := removePageCaptures(ctx, c.repo, c.storage, expired)
+		if err != nil {
+			logging.FromContext(ctx).WithField("user_id", user.UUID).Error("failed to enforce retention: ", err)
+			continue
+		}
+
+		if deleted > 0 {
+			logging.FromContext(ctx).WithField("user_id", user.UUID).Infof("retention removed %d page captures", deleted)
+		}
+	}
+
+	return nil
+}
+
+func (c *PageCaptureUseCase) userByApiKey(key string, ctx context.Context) (*entity.User, error) {
 	redisKey := fmt.Sprintf("api_key:%s", key)
 	cachedKey, err := c.redis.Get(redisKey)
 	if err != nil {
-		logrus.Error("failed to get redis key: ", err)
+		logging.FromContext(ctx).Error("failed to get redis key: ", err)
 		return nil, err
 	}
 
 	if cachedKey == "" {
-		logrus.Error("invalid api key")
+		logging.FromContext(ctx).Error("invalid api key")
 		return nil, errorEntity.ErrInvalidCredentials
 	}
 
@@ -56,68 +591,171 @@ func (c *PageCaptureUseCase) PageCapture(body *dto.PageCaptureRequest, key strin
 		return nil, errorEntity.ErrUserNotFound
 	}
 
-	req := dto.ConvertToScreenshotOptions(body)
-	data, err := rodService.CaptureScreenshot(ctx, c.browserInstance, *req)
+	return user, nil
+}
+
+func (c *PageCaptureUseCase) findPageCapture(id string, ctx context.Context) (*entity.PageCapture, error) {
+	captureID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errorEntity.ErrInvalidRequest
+	}
+
+	capture, err := c.repo.FindByUUID(ctx, captureID)
 	if err != nil {
-		logrus.Error("failed to capture screenshot: ", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorEntity.ErrDataNotFound
+		}
+		logging.FromContext(ctx).Error("failed to find page capture: ", err)
 		return nil, err
 	}
 
-	reader := bytes.NewReader(data)
-	buffer := util.GetBuffer()
-	defer util.PutBuffer(buffer)
-	if _, err := io.Copy(buffer, reader); err != nil {
+	return capture, nil
+}
+
+func (c *PageCaptureUseCase) openPageCapture(capture *entity.PageCapture, ctx context.Context) (*dto.PageCaptureFile, error) {
+	if capture.Status != entity.PageCaptureStatusStored {
+		return nil, errorEntity.ErrCaptureNotStored
+	}
+
+	content, err := c.storage.Open(ctx, capture.PublicId, capture.ContentType)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to open stored page capture: ", err)
 		return nil, err
 	}
 
-	resp := &dto.PageCaptureResponse{
-		Filename: "screenshot.png",
-		Content:  buffer.Bytes(),
+	return &dto.PageCaptureFile{
+		Filename:    fmt.Sprintf("%s.%s", capture.UUID, capture.Format),
+		ContentType: capture.ContentType,
+		Content:     content,
+	}, nil
+}
+
+func (c *PageCaptureUseCase) signPageCapture(id string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(c.cfg.Server.SignedUrlSecret))
+	mac.Write([]byte(fmt.Sprintf("%s:%d", id, expires)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+const (
+	retentionBatchSize = 500
+	artifactThumbnail  = "thumbnail"
+)
+
+// removePageCaptures deletes the stored objects of the given captures and then
+// their rows. Rows whose object could not be removed are kept so the deletion
+// can be retried instead of orphaning the object.
+func removePageCaptures(ctx context.Context, repo repository.PageCaptureRepository, store storage.Service, captures []entity.PageCapture) (int, error) {
+	if len(captures) == 0 {
+		return 0, nil
 	}
 
-	go func(dataCopy []byte, reqCopy dto.PageCaptureRequest, userUUID uuid.UUID) {
-		params := util.UploadParamsPool.Get().(*uploader.UploadParams)
-		defer util.UploadParamsPool.Put(params)
+	captureIDs := make([]uuid.UUID, 0, len(captures))
+	for _, capture := range captures {
+		captureIDs = append(captureIDs, capture.UUID)
+	}
 
-		publicID := fmt.Sprintf("capture/%s", uuid.NewString())
-		overwrite := true
-		params.PublicID = publicID
-		params.Overwrite = &overwrite
-		params.ResourceType = "image"
+	artifacts, err := repo.FindArtifactsByCaptureIDs(ctx, captureIDs)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to find page capture artifacts: ", err)
+		return 0, err
+	}
 
-		uploadResult, err := c.cloud.Upload.Upload(context.Background(), bytes.NewReader(dataCopy), *params)
-		if err != nil {
-			logrus.Error("cloudinary upload failed: ", err)
-			return
-		}
+	artifactsByCapture := make(map[uuid.UUID][]entity.CaptureArtifact, len(captures))
+	for _, artifact := range artifacts {
+		artifactsByCapture[artifact.CaptureID] = append(artifactsByCapture[artifact.CaptureID], artifact)
+	}
 
-		pageCapture := dto.ConvertRequestToEntity(reqCopy, userUUID)
-		pageCapture.PublicId = publicID
-		pageCapture.ImagePath = uploadResult.SecureURL
+	seen := make(map[uuid.UUID]bool, len(captures))
+	removed := map[string]bool{}
+	ids := make([]uuid.UUID, 0, len(captures))
+	var storageErr error
 
-		if err := c.repo.Create(context.Background(), pageCapture); err != nil {
-			logrus.WithFields(logrus.Fields{
-				"user_id": userUUID,
-				"error":   err.Error(),
-			}).Error("database create failed")
-			return
+	for _, capture := range captures {
+		if seen[capture.UUID] {
+			continue
 		}
+		seen[capture.UUID] = true
 
-		logrus.Info("Upload and DB insert completed successfully")
-	}(buffer.Bytes(), *body, user.UUID)
+		if err := removeArtifacts(ctx, store, artifactsByCapture[capture.UUID], removed); err != nil {
+			logging.FromContext(ctx).WithFields(logrus.Fields{
+				"capture_id": capture.UUID,
+				"error":      err.Error(),
+			}).Error("failed to delete stored page capture artifacts")
+			storageErr = err
+			continue
+		}
 
-	return resp, nil
-}
+		if capture.PublicId != "" {
+			if err := store.Delete(ctx, capture.PublicId, capture.ContentType); err != nil {
+				logging.FromContext(ctx).WithFields(logrus.Fields{
+					"capture_id": capture.UUID,
+					"error":      err.Error(),
+				}).Error("failed to delete stored page capture")
+				storageErr = err
+				continue
+			}
+			removed[capture.PublicId] = true
+		}
 
-func (c *PageCaptureUseCase) GetPageCapture(e *entity.User, search string, orderBy string, sort string, page int, fullPage *bool, isMobile *bool) (*dto.PagesCaptureResponse, error) {
-	data, err := c.repo.GetPageCaptureByUserID(e.UUID.String(), search, orderBy, sort, page, fullPage, isMobile)
+		ids = append(ids, capture.UUID)
+	}
+
+	deletedCaptures, deletedArtifacts, err := repo.DeleteByUUIDs(ctx, ids)
 	if err != nil {
-		return nil, err
+		logging.FromContext(ctx).Error("failed to delete page captures: ", err)
+		return 0, err
 	}
 
-	if len(data.Data) == 0 {
-		return nil, errorEntity.ErrDataNotFound
+	// An upload may have completed between reading the captures and deleting
+	// them. Its object is only known from the deleted rows.
+	for _, capture := range deletedCaptures {
+		if capture.PublicId == "" || removed[capture.PublicId] {
+			continue
+		}
+		if err := store.Delete(ctx, capture.PublicId, capture.ContentType); err != nil {
+			logging.FromContext(ctx).WithField("capture_id", capture.UUID).Error("failed to delete page capture stored during deletion: ", err)
+			storageErr = err
+		}
+	}
+	for _, artifact := range deletedArtifacts {
+		if artifact.PublicId == "" || removed[artifact.PublicId] {
+			continue
+		}
+		if err := store.Delete(ctx, artifact.PublicId, artifact.ContentType); err != nil {
+			logging.FromContext(ctx).WithField("artifact_id", artifact.UUID).Error("failed to delete artifact stored during deletion: ", err)
+			storageErr = err
+		}
 	}
 
-	return data, nil
+	if storageErr != nil {
+		return len(ids), fmt.Errorf("failed to delete some stored page captures: %w", storageErr)
+	}
+
+	return len(ids), nil
+}
+
+func removeArtifacts(ctx context.Context, store storage.Service, artifacts []entity.CaptureArtifact, removed map[string]bool) error {
+	for _, artifact := range artifacts {
+		if artifact.PublicId == "" {
+			continue
+		}
+		if err := store.Delete(ctx, artifact.PublicId, artifact.ContentType); err != nil {
+			return err
+		}
+		removed[artifact.PublicId] = true
+	}
+	return nil
+}
+
+// safeFilename keeps letters, digits, dashes and underscores so user supplied
+// names can be used inside archives.
+func safeFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, name)
 }
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/redis"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
+	rodService "github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/rod"
+	"github.com/go-rod/rod"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const templateRenderCacheTtl = 24 * time.Hour
+
+type TemplateUseCase struct {
+	repo            repository.TemplateRepository
+	redis           redis.Service
+	browserInstance *rod.Browser
+}
+
+func NewTemplateUseCase(repo repository.TemplateRepository, redis redis.Service, browser *rod.Browser) *TemplateUseCase {
+	return &TemplateUseCase{
+		repo:            repo,
+		redis:           redis,
+		browserInstance: browser,
+	}
+}
+
+func (t *TemplateUseCase) CreateTemplate(e *entity.User, body *dto.CreateTemplateRequest, ctx context.Context) (*entity.Template, error) {
+	if _, err := parseTemplate(body.Content); err != nil {
+		return nil, err
+	}
+
+	tmpl := entity.NewTemplate(e.UUID, body.Name, body.Description, body.Content, body.Width, body.Height)
+	if err := t.repo.Create(ctx, tmpl); err != nil {
+		logging.FromContext(ctx).Error("failed to create template: ", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("template created successfully")
+	return tmpl, nil
+}
+
+func (t *TemplateUseCase) GetTemplates(e *entity.User, ctx context.Context) ([]entity.Template, error) {
+	templates, err := t.repo.FindByUserID(ctx, e.UUID)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to get templates: ", err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (t *TemplateUseCase) GetTemplate(e *entity.User, id string, ctx context.Context) (*entity.Template, error) {
+	return t.findTemplate(e, id, ctx)
+}
+
+func (t *TemplateUseCase) UpdateTemplate(e *entity.User, id string, body *dto.UpdateTemplateRequest, ctx context.Context) (*entity.Template, error) {
+	tmpl, err := t.findTemplate(e, id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	if body.Content != nil && *body.Content != tmpl.Content {
+		if _, err := parseTemplate(*body.Content); err != nil {
+			return nil, err
+		}
+		tmpl.Content = *body.Content
+		changed = true
+	}
+
+	if body.Width != nil && *body.Width != tmpl.Width {
+		tmpl.Width = *body.Width
+		changed = true
+	}
+
+	if body.Height != nil && *body.Height != tmpl.Height {
+		tmpl.Height = *body.Height
+		changed = true
+	}
+
+	if body.Name != nil {
+		tmpl.Name = *body.Name
+	}
+
+	if body.Description != nil {
+		tmpl.Description = *body.Description
+	}
+
+	if changed {
+		tmpl.Version++
+	}
+	tmpl.UpdatedAt = time.Now()
+
+	if err := t.repo.Update(ctx, tmpl); err != nil {
+		logging.FromContext(ctx).Error("failed to update template: ", err)
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Info("template updated successfully")
+	return tmpl, nil
+}
+
+func (t *TemplateUseCase) DeleteTemplate(e *entity.User, id string, ctx context.Context) error {
+	tmpl, err := t.findTemplate(e, id, ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := t.repo.Delete(ctx, tmpl); err != nil {
+		logging.FromContext(ctx).Error("failed to delete template: ", err)
+		return err
+	}
+
+	logging.FromContext(ctx).Info("template deleted successfully")
+	return nil
+}
+
+// RenderTemplate fills the template with the given variables and captures it
+// as PNG or PDF. Renders are cached per template version and variable hash.
+func (t *TemplateUseCase) RenderTemplate(e *entity.User, id string, body *dto.RenderTemplateRequest, ctx context.Context) (*dto.RenderTemplateResponse, error) {
+	tmpl, err := t.findTemplate(e, id, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	format := body.Format
+	if format == "" {
+		format = "png"
+	}
+
+	contentType := "image/png"
+	if format == "pdf" {
+		contentType = "application/pdf"
+	}
+
+	filename := fmt.Sprintf("%s.%s", tmpl.UUID, format)
+
+	hash, err := renderHash(body.Variables, format, body.FullPage)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to hash template variables: ", err)
+		return nil, err
+	}
+	cacheKey := fmt.Sprintf("template_render:%s:%d:%s", tmpl.UUID, tmpl.Version, hash)
+
+	if cached, err := t.redis.Get(cacheKey); err == nil && cached != "" {
+		logging.FromContext(ctx).Info("template render served from cache")
+		return &dto.RenderTemplateResponse{
+			Filename:    filename,
+			ContentType: contentType,
+			Content:     []byte(cached),
+			CacheHit:    true,
+		}, nil
+	}
+
+	parsed, err := parseTemplate(tmpl.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	if err := parsed.Execute(&html, body.Variables); err != nil {
+		logging.FromContext(ctx).Warn("failed to execute template: ", err)
+		return nil, fmt.Errorf("%w: %v", errorEntity.ErrInvalidRequest, err)
+	}
+
+	result, err := rodService.CaptureScreenshot(ctx, t.browserInstance, rodService.ScreenshotOptions{
+		HTML:     html.String(),
+		Width:    tmpl.Width,
+		Height:   tmpl.Height,
+		FullPage: body.FullPage,
+		PDF:      format == "pdf",
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to render template: ", err)
+		return nil, err
+	}
+
+	if err := t.redis.Set(cacheKey, result.Image, templateRenderCacheTtl); err != nil {
+		logging.FromContext(ctx).Warn("failed to cache template render: ", err)
+	}
+
+	return &dto.RenderTemplateResponse{
+		Filename:    filename,
+		ContentType: contentType,
+		Content:     result.Image,
+	}, nil
+}
+
+func (t *TemplateUseCase) findTemplate(e *entity.User, id string, ctx context.Context) (*entity.Template, error) {
+	templateID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errorEntity.ErrInvalidRequest
+	}
+
+	tmpl, err := t.repo.FindByUUID(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorEntity.ErrDataNotFound
+		}
+		logging.FromContext(ctx).Error("failed to find template: ", err)
+		return nil, err
+	}
+
+	if tmpl.UserID != e.UUID {
+		logging.FromContext(ctx).Warn("template does not belong to user")
+		return nil, errorEntity.ErrDataNotFound
+	}
+
+	return tmpl, nil
+}
+
+// parseTemplate parses content with html/template, which escapes variables
+// for the HTML, attribute, URL, CSS or JS context they appear in. Missing
+// variables are reported instead of rendering as "<no value>".
+func parseTemplate(content string) (*template.Template, error) {
+	parsed, err := template.New("template").Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errorEntity.ErrInvalidRequest, err)
+	}
+	return parsed, nil
+}
+
+func renderHash(variables map[string]any, format string, fullPage bool) (string, error) {
+	raw, err := json.Marshal(struct {
+		Variables map[string]any `json:"variables"`
+		Format    string         `json:"format"`
+		FullPage  bool           `json:"full_page"`
+	}{variables, format, fullPage})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/storage"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	uploadLease       = 5 * time.Minute
+	uploadBaseBackoff = 5 * time.Second
+	uploadMaxBackoff  = 10 * time.Minute
+)
+
+// UploadUseCase owns the upload outbox: captures are enqueued together with
+// their bytes and stored asynchronously with retries and dead-lettering.
+type UploadUseCase struct {
+	captureRepo repository.PageCaptureRepository
+	jobRepo     repository.UploadJobRepository
+	storage     storage.Service
+	maxAttempts int
+	notify      chan struct{}
+}
+
+func NewUploadUseCase(captureRepo repository.PageCaptureRepository, jobRepo repository.UploadJobRepository, storage storage.Service, maxAttempts int) *UploadUseCase {
+	return &UploadUseCase{
+		captureRepo: captureRepo,
+		jobRepo:     jobRepo,
+		storage:     storage,
+		maxAttempts: maxAttempts,
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+// PendingArtifact pairs an artifact row with the bytes to upload for it.
+type PendingArtifact struct {
+	Artifact *entity.CaptureArtifact
+	Data     []byte
+}
+
+// Enqueue records the capture and its artifacts as pending and queues their
+// bytes for upload.
+func (u *UploadUseCase) Enqueue(ctx context.Context, capture *entity.PageCapture, data []byte, contentType string, artifacts ...PendingArtifact) error {
+	if capture.UUID == uuid.Nil {
+		capture.UUID = uuid.New()
+	}
+	capture.Status = entity.PageCaptureStatusPending
+	capture.ContentType = contentType
+
+	key := fmt.Sprintf("capture/%s", capture.UUID)
+	jobs := []*entity.UploadJob{entity.NewUploadJob(capture.UUID, key, contentType, data)}
+
+	capture.Artifacts = make([]entity.CaptureArtifact, 0, len(artifacts))
+	for _, pending := range artifacts {
+		artifact := pending.Artifact
+		artifact.CaptureID = capture.UUID
+		capture.Artifacts = append(capture.Artifacts, *artifact)
+
+		job := entity.NewUploadJob(capture.UUID, fmt.Sprintf("capture/%s/%s", capture.UUID, artifact.Filename), artifact.ContentType, pending.Data)
+		job.ArtifactID = &artifact.UUID
+		jobs = append(jobs, job)
+	}
+
+	traceParent := tracing.TraceParent(ctx)
+	for _, job := range jobs {
+		job.TraceParent = traceParent
+	}
+
+	if err := u.captureRepo.CreatePending(ctx, capture, jobs); err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"user_id": capture.UserID,
+			"error":   err.Error(),
+		}).Error("failed to enqueue page capture upload")
+		return err
+	}
+
+	select {
+	case u.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Notifications fires when new uploads have been enqueued.
+func (u *UploadUseCase) Notifications() <-chan struct{} {
+	return u.notify
+}
+
+// ProcessDue claims up to limit due jobs and attempts to upload each of them.
+// It returns the number of jobs claimed.
+func (u *UploadUseCase) ProcessDue(ctx context.Context, limit int) (int, error) {
+	jobs, err := u.jobRepo.ClaimDue(ctx, limit, uploadLease)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to claim upload jobs: ", err)
+		return 0, err
+	}
+
+	for i := range jobs {
+		u.process(ctx, &jobs[i])
+	}
+
+	return len(jobs), nil
+}
+
+// PendingCount returns the number of uploads that are ready to run.
+func (u *UploadUseCase) PendingCount(ctx context.Context) (int64, error) {
+	return u.jobRepo.CountDue(ctx)
+}
+
+// process runs in its own trace, linked to the request that enqueued the job.
+func (u *UploadUseCase) process(ctx context.Context, job *entity.UploadJob) {
+	ctx, span := tracing.Start(ctx, "UploadUseCase.process", append(tracing.LinkTo(job.TraceParent),
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String("capture.id", job.CaptureID.String()),
+			attribute.Int("upload.attempt", job.Attempts+1),
+		),
+	)...)
+	defer span.End()
+
+	log := logging.FromContext(ctx).WithFields(logrus.Fields{
+		"capture_id": job.CaptureID,
+		"attempt":    job.Attempts + 1,
+	})
+	if job.ArtifactID != nil {
+		log = log.WithField("artifact_id", *job.ArtifactID)
+	}
+
+	start := time.Now()
+	object, err := u.storage.Upload(ctx, job.Key, job.Payload, job.ContentType)
+	metrics.CapturePhaseDuration.WithLabelValues(metrics.PhaseUpload).Observe(time.Since(start).Seconds())
+	tracing.RecordError(span, err)
+
+	// An upload cut short by shutdown is not a failed attempt. Its job is
+	// released rather than left leased, and the bookkeeping below must not
+	// fail on the cancelled ctx either.
+	interrupted := err != nil && ctx.Err() != nil
+	ctx = context.WithoutCancel(ctx)
+	if interrupted {
+		log.Warn("upload interrupted, releasing the job: ", err)
+		if err := u.jobRepo.Release(ctx, job); err != nil {
+			log.Error("failed to release upload job: ", err)
+		}
+		return
+	}
+
+	if err == nil {
+		metrics.Uploads.WithLabelValues("stored").Inc()
+		if err := u.jobRepo.Complete(ctx, job, object.Key, object.URL); err != nil {
+			if errors.Is(err, errorEntity.ErrDataNotFound) {
+				log.Warn("page capture was deleted during upload, removing the stored object")
+				if err := u.storage.Delete(ctx, object.Key, job.ContentType); err != nil {
+					log.Error("failed to delete orphaned upload: ", err)
+				}
+				return
+			}
+			log.Error("failed to complete upload job: ", err)
+			return
+		}
+		log.Info("page capture stored successfully")
+		return
+	}
+
+	if job.Attempts+1 >= u.maxAttempts {
+		metrics.Uploads.WithLabelValues("dead_letter").Inc()
+		log.Error("upload failed permanently, moving to dead letter: ", err)
+		if err := u.jobRepo.DeadLetter(ctx, job, err); err != nil {
+			log.Error("failed to dead letter upload job: ", err)
+		}
+		return
+	}
+
+	backoff := uploadBaseBackoff << job.Attempts
+	if backoff <= 0 || backoff > uploadMaxBackoff {
+		backoff = uploadMaxBackoff
+	}
+
+	metrics.Uploads.WithLabelValues("retry").Inc()
+	log.Warnf("upload failed, retrying in %s: %v", backoff, err)
+	if err := u.jobRepo.Retry(ctx, job, err, time.Now().Add(backoff)); err != nil {
+		log.Error("failed to reschedule upload job: ", err)
+	}
+}
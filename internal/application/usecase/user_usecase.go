@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/redis"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/storage"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/util"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
 	"github.com/cloudinary/cloudinary-go/v2"
@@ -23,59 +25,64 @@ import (
 )
 
 type UserUseCase struct {
-	repo  repository.UserRepository
-	redis redis.Service
-	cloud *cloudinary.Cloudinary
-	cfg   *config.Config
+	repo         repository.UserRepository
+	captureRepo  repository.PageCaptureRepository
+	templateRepo repository.TemplateRepository
+	redis        redis.Service
+	cloud        *cloudinary.Cloudinary
+	storage      storage.Service
+	cfg          *config.Config
 }
 
-func NewUserUseCase(repo repository.UserRepository, redis redis.Service, cfg *config.Config, cloud *cloudinary.Cloudinary) *UserUseCase {
+func NewUserUseCase(repo repository.UserRepository, captureRepo repository.PageCaptureRepository, templateRepo repository.TemplateRepository, redis redis.Service, cfg *config.Config, cloud *cloudinary.Cloudinary, storage storage.Service) *UserUseCase {
 	return &UserUseCase{
-		repo:  repo,
-		redis: redis,
-		cloud: cloud,
-		cfg:   cfg,
+		repo:         repo,
+		captureRepo:  captureRepo,
+		templateRepo: templateRepo,
+		redis:        redis,
+		cloud:        cloud,
+		storage:      storage,
+		cfg:          cfg,
 	}
 }
 
-func (c *UserUseCase) ChangePassword(d *dto.ChangePasswordRequest, e *entity.User, context context.Context) error {
+func (c *UserUseCase) ChangePassword(d *dto.ChangePasswordRequest, e *entity.User, ctx context.Context) error {
 	if e.Password == "" {
-		logrus.WithError(errorEntity.ErrPasswordNotSet).Error("password is not set")
+		logging.FromContext(ctx).WithError(errorEntity.ErrPasswordNotSet).Error("password is not set")
 		return errorEntity.ErrPasswordNotSet
 	}
 
 	if response := util.ComparePassword(e.Password, d.OldPassword, c.cfg.Server.Salt); !response {
-		logrus.Info("password not match")
+		logging.FromContext(ctx).Info("password not match")
 		return errorEntity.ErrInvalidPassword
 	}
 
 	hashedPassword := util.HashPassword(d.NewPassword, c.cfg.Server.Salt)
 	e.Password = hashedPassword
-	if err := c.repo.Update(context, e); err != nil {
-		logrus.WithError(err).Error("failed to update password")
+	if err := c.repo.Update(ctx, e); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("failed to update password")
 		return err
 	}
 
 	if err := c.redis.Delete(fmt.Sprintf("user:%s", e.UUID)); err != nil {
-		logrus.WithError(err).Error("failed to delete user cache")
+		logging.FromContext(ctx).WithError(err).Error("failed to delete user cache")
 		return err
 	}
 
-	accessExpire, _ := tm.ParseDuration(c.cfg.Jwt.AccessTokenExpire)
 	jsonUser, _ := json.Marshal(e)
-	if err := c.redis.Set(fmt.Sprintf("user:%s", e.UUID), jsonUser, accessExpire); err != nil {
-		logrus.WithError(err).Error("failed to set user cache")
+	if err := c.redis.Set(fmt.Sprintf("user:%s", e.UUID), jsonUser, c.cfg.Jwt.AccessTokenExpire); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("failed to set user cache")
 		return err
 	}
 
-	logrus.Info("password updated successfully")
+	logging.FromContext(ctx).Info("password updated successfully")
 	return nil
 }
 
 func (c *UserUseCase) UpdateUserProfile(d *dto.UpdateUserProfileRequest, e *entity.User, ctx context.Context) error {
 	if strings.TrimSpace(d.Name) != "" {
 		e.Name = strings.TrimSpace(d.Name)
-		logrus.Info("name updated successfully")
+		logging.FromContext(ctx).Info("name updated successfully")
 	}
 
 	if d.ProfilePicture != nil {
@@ -107,7 +114,7 @@ func (c *UserUseCase) UpdateUserProfile(d *dto.UpdateUserProfileRequest, e *enti
 
 		uploadResult, err := c.cloud.Upload.Upload(ctx, bytes.NewReader(buffer.Bytes()), *params)
 		if err != nil {
-			logrus.Error("cloudinary upload failed")
+			logging.FromContext(ctx).Error("cloudinary upload failed")
 			return fmt.Errorf("%w: %v", errorEntity.ErrCloudinaryUpload, err)
 		}
 
@@ -124,16 +131,16 @@ func (c *UserUseCase) UpdateUserProfile(d *dto.UpdateUserProfileRequest, e *enti
 					PublicID: pubID,
 				})
 				if err != nil {
-					logrus.WithError(err).Error("failed to delete old profile picture")
+					logging.FromContext(ctx).WithError(err).Error("failed to delete old profile picture")
 				}
 			}(oldPublicID)
 		}
 
-		logrus.Info("cloudinary upload successful")
+		logging.FromContext(ctx).Info("cloudinary upload successful")
 	}
 
 	if err := c.repo.Update(ctx, e); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logging.FromContext(ctx).WithFields(logrus.Fields{
 			"user_id": e.UUID,
 			"error":   err.Error(),
 		}).Error("database update failed")
@@ -142,13 +149,12 @@ func (c *UserUseCase) UpdateUserProfile(d *dto.UpdateUserProfileRequest, e *enti
 
 	jsonUser, err := json.Marshal(e)
 	if err != nil {
-		logrus.WithError(err).Error("failed to marshal user data")
+		logging.FromContext(ctx).WithError(err).Error("failed to marshal user data")
 		return err
 	}
 
-	expire, _ := tm.ParseDuration(c.cfg.Jwt.AccessTokenExpire)
-	if err := c.redis.Set(fmt.Sprintf("user:%s", e.UUID), jsonUser, expire); err != nil {
-		logrus.WithError(err).Error("failed to set user cache")
+	if err := c.redis.Set(fmt.Sprintf("user:%s", e.UUID), jsonUser, c.cfg.Jwt.AccessTokenExpire); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("failed to set user cache")
 		return err
 	}
 
@@ -165,19 +171,19 @@ func (c *UserUseCase) DeleteUser(e *entity.User, body *dto.DeleteRequest, ctx co
 		defer wg.Done()
 
 		if err := c.redis.Delete(fmt.Sprintf("user:%s", e.UUID)); err != nil {
-			logrus.WithError(err).Error("failed to delete user cache")
+			logging.FromContext(ctx).WithError(err).Error("failed to delete user cache")
 			errHandler.SetError(err)
 			return
 		}
 
 		if err := c.redis.Delete(fmt.Sprintf("user_refresh:%s", e.UUID)); err != nil {
-			logrus.WithError(err).Error("failed to delete user refresh token cache")
+			logging.FromContext(ctx).WithError(err).Error("failed to delete user refresh token cache")
 			errHandler.SetError(err)
 			return
 		}
 
 		if err := c.redis.Set(fmt.Sprintf("blacklist:%s", body.RefreshToken), "true", 0); err != nil {
-			logrus.WithError(err).Error("failed to blacklist refresh token")
+			logging.FromContext(ctx).WithError(err).Error("failed to blacklist refresh token")
 			errHandler.SetError(err)
 		}
 	}()
@@ -185,8 +191,35 @@ func (c *UserUseCase) DeleteUser(e *entity.User, body *dto.DeleteRequest, ctx co
 	go func() {
 		defer wg.Done()
 
+		captures, err := c.captureRepo.FindByUserID(ctx, e.UUID)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("failed to find user page captures")
+			errHandler.SetError(err)
+			return
+		}
+
+		if _, err := removePageCaptures(ctx, c.captureRepo, c.storage, captures); err != nil {
+			logging.FromContext(ctx).WithError(err).Error("failed to delete user page captures")
+			errHandler.SetError(err)
+			return
+		}
+
+		if e.PublicId != "" {
+			if err := c.storage.Delete(ctx, e.PublicId, "image/*"); err != nil {
+				logging.FromContext(ctx).WithError(err).Error("failed to delete profile picture")
+				errHandler.SetError(err)
+				return
+			}
+		}
+
+		if err := c.templateRepo.DeleteByUserID(ctx, e.UUID); err != nil {
+			logging.FromContext(ctx).WithError(err).Error("failed to delete user templates")
+			errHandler.SetError(err)
+			return
+		}
+
 		if err := c.repo.Delete(ctx, e); err != nil {
-			logrus.WithError(err).Error("failed to delete user from database")
+			logging.FromContext(ctx).WithError(err).Error("failed to delete user from database")
 			errHandler.SetError(err)
 		}
 	}()
@@ -197,6 +230,24 @@ func (c *UserUseCase) DeleteUser(e *entity.User, body *dto.DeleteRequest, ctx co
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
-	logrus.Info("user deleted successfully")
+	logging.FromContext(ctx).Info("user deleted successfully")
+	return nil
+}
+
+func (c *UserUseCase) UpdateRetention(d *dto.UpdateRetentionRequest, e *entity.User, ctx context.Context) error {
+	e.RetentionDays = d.RetentionDays
+	e.RetentionMaxCaptures = d.RetentionMaxCaptures
+
+	if err := c.repo.Update(ctx, e); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("failed to update retention policy")
+		return err
+	}
+
+	if err := c.redis.Delete(fmt.Sprintf("user:%s", e.UUID)); err != nil {
+		logging.FromContext(ctx).WithError(err).Error("failed to delete user cache")
+		return err
+	}
+
+	logging.FromContext(ctx).Info("retention policy updated successfully")
 	return nil
 }
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// RetentionSweeper periodically removes page captures that fall outside their
+// owner's retention policy.
+type RetentionSweeper struct {
+	pageCapture *usecase.PageCaptureUseCase
+	interval    time.Duration
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+func NewRetentionSweeper(pageCapture *usecase.PageCaptureUseCase, interval time.Duration) *RetentionSweeper {
+	return &RetentionSweeper{
+		pageCapture: pageCapture,
+		interval:    interval,
+	}
+}
+
+func (s *RetentionSweeper) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		logrus.Infof("Retention sweeper started (interval %s)", s.interval)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.pageCapture.EnforceRetention(ctx); err != nil && ctx.Err() == nil {
+					logrus.WithError(err).Error("retention sweep failed")
+				}
+			}
+		}
+	}()
+}
+
+// Stop cancels any sweep in progress and waits for the sweeper to exit.
+func (s *RetentionSweeper) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	logrus.Info("Retention sweeper stopped")
+}
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+const uploadBatchSize = 10
+
+// UploadWorker drains the upload outbox. It polls on an interval and is woken
+// up early whenever a new capture is enqueued.
+type UploadWorker struct {
+	uploads  *usecase.UploadUseCase
+	interval time.Duration
+	stop     chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewUploadWorker(uploads *usecase.UploadUseCase, interval time.Duration) *UploadWorker {
+	return &UploadWorker{
+		uploads:  uploads,
+		interval: interval,
+	}
+}
+
+func (w *UploadWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.stop = make(chan struct{})
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		logrus.Infof("Upload worker started (interval %s)", w.interval)
+		for {
+			w.runBatches(ctx)
+			w.reportQueueDepth(ctx)
+
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			case <-w.uploads.Notifications():
+			}
+		}
+	}()
+}
+
+// Stop halts polling and then drains the outbox until it is empty or ctx is
+// done, so uploads enqueued before shutdown are not left waiting for the next
+// start. A batch already in flight finishes first; it is only cancelled, and
+// its jobs released, once ctx is done.
+func (w *UploadWorker) Stop(ctx context.Context) {
+	if w.cancel == nil {
+		return
+	}
+	close(w.stop)
+	stopAfter := context.AfterFunc(ctx, w.cancel)
+	defer stopAfter()
+	w.wg.Wait()
+	w.cancel()
+
+	w.runBatches(ctx)
+
+	if pending, err := w.uploads.PendingCount(context.Background()); err == nil && pending > 0 {
+		logrus.Warnf("Upload worker stopped with %d pending uploads", pending)
+		return
+	}
+	logrus.Info("Upload worker stopped")
+}
+
+func (w *UploadWorker) runBatches(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := w.uploads.ProcessDue(ctx, uploadBatchSize)
+		if err != nil || claimed < uploadBatchSize {
+			return
+		}
+	}
+}
+
+func (w *UploadWorker) reportQueueDepth(ctx context.Context) {
+	pending, err := w.uploads.PendingCount(ctx)
+	if err != nil {
+		return
+	}
+	metrics.UploadQueueDepth.Set(float64(pending))
+}
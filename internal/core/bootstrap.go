@@ -1,28 +1,48 @@
 package core
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	docs "github.com/SyahrulBhudiF/Doc-Management.git/docs"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/worker"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/core/module"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/cloudinary"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/database"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/jwt"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/mail"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/oauth2/google"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/persistence"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/redis"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/rod"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/midleware"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/route"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
 	"github.com/gin-gonic/gin"
 	"github.com/markbates/goth/gothic"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"net/http"
 )
 
 type App struct {
-	Router *gin.Engine
+	Router           *gin.Engine
+	Server           *http.Server
+	RetentionSweeper *worker.RetentionSweeper
+	UploadWorker     *worker.UploadWorker
+	Health           *usecase.HealthUseCase
+	ShutdownTimeout  time.Duration
+	ReadinessDelay   time.Duration
+	db               *gorm.DB
+	redis            *goredis.Client
+	shutdownTracing  func(context.Context) error
 }
 
 func Bootstrap() (*App, error) {
@@ -31,6 +51,15 @@ func Bootstrap() (*App, error) {
 		return nil, err
 	}
 
+	if err := logging.Setup(cfg.Logging.Format, cfg.Logging.Level); err != nil {
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q or LOG_LEVEL %q: %w", cfg.Logging.Format, cfg.Logging.Level, err)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRACING_EXPORTER %q: %w", cfg.Tracing.Exporter, err)
+	}
+
 	db, err := database.NewPostgresDB(cfg)
 	if err != nil {
 		return nil, err
@@ -54,6 +83,31 @@ func Bootstrap() (*App, error) {
 
 	// Cloudinary
 	cloudinaryService := cloudinary.NewCloudinary(cfg)
+	storageService := cloudinary.NewStorageService(cloudinaryService)
+
+	// Browser proxies, isolation and timeouts
+	if cfg.Browser.Proxy != "" {
+		proxy, err := rod.ParseProxy(cfg.Browser.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BROWSER_PROXY: %w", err)
+		}
+		rod.SetDefaultProxy(proxy)
+	}
+	proxyPool, err := rod.ParseProxyPool(cfg.Browser.ProxyPool)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY_POOL: %w", err)
+	}
+	rod.SetProxyPool(proxyPool)
+	if err := rod.SetIsolation(cfg.Browser.Isolation); err != nil {
+		return nil, fmt.Errorf("invalid BROWSER_ISOLATION: %w", err)
+	}
+	if err := rod.SetCaptureTimeouts(cfg.Browser.CaptureTimeout, cfg.Browser.MaxCaptureTimeout); err != nil {
+		return nil, err
+	}
+
+	rod.SetHeadless(cfg.Browser.Headless)
+	rod.SetTabLimit(cfg.Browser.TabLimit)
+	rod.SetIdleTimeout(cfg.Browser.IdleTimeout)
 
 	// Init Browser
 	browser, err := rod.InitBrowser()
@@ -64,20 +118,96 @@ func Bootstrap() (*App, error) {
 	// Repositories
 	userRepo := persistence.NewUserRepository(db)
 	pageCaptureRepo := persistence.NewPageCaptureRepository(db)
+	uploadJobRepo := persistence.NewUploadJobRepository(db)
+	templateRepo := persistence.NewTemplateRepository(db)
+
+	// Upload outbox
+	uploadUC := usecase.NewUploadUseCase(pageCaptureRepo, uploadJobRepo, storageService, cfg.Upload.MaxAttempts)
 
 	// Initialize middleware
 	authMiddleware := midleware.NewAuthMiddleware(userRepo, redisRepo, jwtService, cfg)
 
 	// Initialize Modules
 	authHandler := module.InitAuthModule(cfg, userRepo, jwtService, mailService, redisRepo)
-	userHandler := module.InitUserModule(cfg, userRepo, redisRepo, cloudinaryService)
-	pageCaptureHandler := module.InitPageCaptureModule(cfg, pageCaptureRepo, redisRepo, cloudinaryService, browser)
+	userHandler := module.InitUserModule(cfg, userRepo, pageCaptureRepo, templateRepo, redisRepo, cloudinaryService, storageService)
+	pageCaptureHandler, pageCaptureUC := module.InitPageCaptureModule(cfg, pageCaptureRepo, redisRepo, storageService, uploadUC, browser)
+	templateHandler := module.InitTemplateModule(templateRepo, redisRepo, browser)
+	healthHandler, healthUC := module.InitHealthModule(db, redisRepo, storageService)
+	adminHandler := module.InitAdminModule(cfg)
+
+	// Background workers
+	retentionSweeper := worker.NewRetentionSweeper(pageCaptureUC, cfg.Retention.SweepInterval)
+	retentionSweeper.Start()
+
+	uploadWorker := worker.NewUploadWorker(uploadUC, cfg.Upload.PollInterval)
+	uploadWorker.Start()
 
 	// Router
 	docs.SwaggerInfo.BasePath = "/api/v1"
-	r := route.NewRoute(authHandler, authMiddleware, userHandler, pageCaptureHandler)
+	r := route.NewRoute(cfg, authHandler, authMiddleware, userHandler, pageCaptureHandler, templateHandler, healthHandler, adminHandler)
 	router := r.RegisterRoutes()
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
-	return &App{Router: router}, nil
+	return &App{
+		Router:           router,
+		Server:           &http.Server{Addr: fmt.Sprintf(":%d", cfg.Server.Port), Handler: router},
+		RetentionSweeper: retentionSweeper,
+		UploadWorker:     uploadWorker,
+		Health:           healthUC,
+		ShutdownTimeout:  cfg.Server.ShutdownTimeout,
+		ReadinessDelay:   cfg.Server.ReadinessDelay,
+		db:               db,
+		redis:            rd,
+		shutdownTracing:  shutdownTracing,
+	}, nil
+}
+
+// Shutdown drains the service in order: readiness fails and the server keeps
+// serving for ReadinessDelay, so load balancers see the failing probe and
+// stop routing to it, then the server stops accepting connections and waits
+// for in-flight requests, the workers stop
+// and pending uploads are drained, then the browser, tracing, database and
+// Redis are closed. Every step shares ctx's deadline. Requests still running
+// when it passes fail once the browser closes, and uploads left over stay in
+// the outbox for the next start.
+func (a *App) Shutdown(ctx context.Context) {
+	a.Health.MarkDraining()
+	if a.ReadinessDelay > 0 {
+		logrus.Infof("Readiness failing, waiting %s before closing the listener", a.ReadinessDelay)
+		select {
+		case <-time.After(a.ReadinessDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	serverErr := a.Server.Shutdown(ctx)
+	if serverErr != nil {
+		logrus.Warn("in-flight requests did not finish before the shutdown deadline: ", serverErr)
+	} else {
+		logrus.Info("HTTP server stopped")
+	}
+
+	a.RetentionSweeper.Stop()
+	a.UploadWorker.Stop(ctx)
+	rod.Shutdown()
+
+	if serverErr != nil {
+		if err := a.Server.Close(); err != nil {
+			logrus.Warn("failed to close remaining connections: ", err)
+		}
+	}
+
+	if err := a.shutdownTracing(ctx); err != nil {
+		logrus.Warn("failed to flush traces: ", err)
+	}
+
+	if sqlDB, err := a.db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			logrus.Warn("failed to close database: ", err)
+		}
+	}
+	if err := a.redis.Close(); err != nil {
+		logrus.Warn("failed to close redis: ", err)
+	}
+	logrus.Info("Shutdown complete")
 }
@@ -0,0 +1,14 @@
+package module
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
+)
+
+func InitAdminModule(cfg *config.Config) *handler.AdminHandler {
+	adminUC := usecase.NewAdminUseCase(cfg)
+	adminHandler := handler.NewAdminHandler(adminUC)
+
+	return adminHandler
+}
@@ -0,0 +1,33 @@
+package module
+
+import (
+	"context"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	redisContract "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/redis"
+	storageContract "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/storage"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/database"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/rod"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
+	"gorm.io/gorm"
+)
+
+func InitHealthModule(db *gorm.DB, redis redisContract.Service, storage storageContract.Service) (*handler.HealthHandler, *usecase.HealthUseCase) {
+	healthUC := usecase.NewHealthUseCase(
+		usecase.HealthCheck{Name: "postgres", Check: func(ctx context.Context) (any, error) {
+			return nil, database.Ping(ctx, db)
+		}},
+		usecase.HealthCheck{Name: "redis", Check: func(ctx context.Context) (any, error) {
+			return nil, redis.Ping(ctx)
+		}},
+		usecase.HealthCheck{Name: "storage", Check: func(ctx context.Context) (any, error) {
+			return nil, storage.Ping(ctx)
+		}},
+		usecase.HealthCheck{Name: "browser", Check: func(ctx context.Context) (any, error) {
+			return rod.Ping(ctx)
+		}},
+	)
+	healthHandler := handler.NewHealthHandler(healthUC)
+
+	return healthHandler, healthUC
+}
@@ -0,0 +1,16 @@
+package module
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	redisContract "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/redis"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
+	"github.com/go-rod/rod"
+)
+
+func InitTemplateModule(repo repository.TemplateRepository, redis redisContract.Service, browser *rod.Browser) *handler.TemplateHandler {
+	templateUC := usecase.NewTemplateUseCase(repo, redis, browser)
+	templateHandler := handler.NewTemplateHandler(templateUC)
+
+	return templateHandler
+}
@@ -4,13 +4,14 @@ import (
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
 	redisContract "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/redis"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	storageContract "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/storage"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
 	"github.com/cloudinary/cloudinary-go/v2"
 )
 
-func InitUserModule(cfg *config.Config, repo repository.UserRepository, redis redisContract.Service, cloud *cloudinary.Cloudinary) *handler.UserHandler {
-	userUC := usecase.NewUserUseCase(repo, redis, cfg, cloud)
+func InitUserModule(cfg *config.Config, repo repository.UserRepository, captureRepo repository.PageCaptureRepository, templateRepo repository.TemplateRepository, redis redisContract.Service, cloud *cloudinary.Cloudinary, storage storageContract.Service) *handler.UserHandler {
+	userUC := usecase.NewUserUseCase(repo, captureRepo, templateRepo, redis, cfg, cloud, storage)
 	userHandler := handler.NewUserHandler(userUC)
 
 	return userHandler
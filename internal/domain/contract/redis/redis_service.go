@@ -1,6 +1,9 @@
 package redis
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Service interface {
 	// Set stores a key-value pair with an expiration time
@@ -23,4 +26,7 @@ type Service interface {
 
 	// Expire sets a new expiration time for a key
 	Expire(key string, expiration time.Duration) error
+
+	// Ping checks that Redis is reachable
+	Ping(ctx context.Context) error
 }
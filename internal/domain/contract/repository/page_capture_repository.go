@@ -1,13 +1,28 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	_interface "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/repository/interface"
+	"github.com/google/uuid"
 )
 
 type PageCaptureRepository interface {
 	_interface.IRepository[entity.PageCapture]
 	GetUser(userID string) (*entity.User, error)
-	GetPageCaptureByUserID(userID string, search string, orderBy string, sort string, page int, fullPage *bool, isMobile *bool) (*dto.PagesCaptureResponse, error)
+	GetPageCaptureByUserID(ctx context.Context, userID string, query *dto.PageCaptureQuery) (*dto.PagesCaptureResponse, error)
+	CreatePending(ctx context.Context, capture *entity.PageCapture, jobs []*entity.UploadJob) error
+	GetUsersWithRetention(ctx context.Context) ([]entity.User, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.PageCapture, error)
+	FindByUserIDAndUUIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]entity.PageCapture, error)
+	FindCreatedBefore(ctx context.Context, userID uuid.UUID, before time.Time, limit int) ([]entity.PageCapture, error)
+	FindBeyondLatest(ctx context.Context, userID uuid.UUID, keep int, limit int) ([]entity.PageCapture, error)
+	// DeleteByUUIDs removes the captures with their artifacts and upload jobs,
+	// returning the rows as they were when deleted
+	DeleteByUUIDs(ctx context.Context, ids []uuid.UUID) ([]entity.PageCapture, []entity.CaptureArtifact, error)
+	FindArtifactsByCaptureIDs(ctx context.Context, captureIDs []uuid.UUID) ([]entity.CaptureArtifact, error)
+	FindArtifact(ctx context.Context, captureID uuid.UUID, artifactID uuid.UUID) (*entity.CaptureArtifact, error)
 }
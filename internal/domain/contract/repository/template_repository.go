@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	_interface "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/repository/interface"
+	"github.com/google/uuid"
+)
+
+type TemplateRepository interface {
+	_interface.IRepository[entity.Template]
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Template, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	_interface "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/repository/interface"
+)
+
+type UploadJobRepository interface {
+	_interface.IRepository[entity.UploadJob]
+	// ClaimDue locks up to limit due jobs and leases them for the given duration
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.UploadJob, error)
+	// Complete marks the job's capture or artifact as stored and removes the job.
+	// It returns ErrDataNotFound when the capture or artifact was deleted
+	// during the upload
+	Complete(ctx context.Context, job *entity.UploadJob, key string, url string) error
+	// Retry records a failed attempt and schedules the next one
+	Retry(ctx context.Context, job *entity.UploadJob, cause error, next time.Time) error
+	// Release ends the job's lease without counting an attempt, so it can be
+	// claimed again right away
+	Release(ctx context.Context, job *entity.UploadJob) error
+	// DeadLetter parks the job and marks its capture or artifact as failed
+	DeadLetter(ctx context.Context, job *entity.UploadJob, cause error) error
+	// CountDue returns the number of pending jobs that are ready to run
+	CountDue(ctx context.Context) (int64, error)
+}
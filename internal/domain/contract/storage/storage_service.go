@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+type Object struct {
+	Key         string
+	URL         string
+	ContentType string
+}
+
+type Service interface {
+	// Upload stores data under the given key and returns the stored object
+	Upload(ctx context.Context, key string, data []byte, contentType string) (*Object, error)
+
+	// Open streams the object stored under the given key
+	Open(ctx context.Context, key string, contentType string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under the given key
+	Delete(ctx context.Context, key string, contentType string) error
+
+	// Ping checks that the storage backend is reachable with the configured credentials
+	Ping(ctx context.Context) error
+}
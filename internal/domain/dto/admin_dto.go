@@ -0,0 +1,14 @@
+package dto
+
+// ConfigResponse is the effective configuration, with secrets redacted.
+// Source is "env", "file" or "default".
+type ConfigResponse struct {
+	File     string          `json:"file,omitempty" example:"/etc/pagecap/config.yaml"`
+	Settings []ConfigSetting `json:"settings"`
+}
+
+type ConfigSetting struct {
+	Key    string `json:"key" example:"SERVER_PORT"`
+	Value  string `json:"value" example:"8080"`
+	Source string `json:"source" example:"env"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+// ReadinessResponse reports the service state, "starting", "ready" or
+// "draining", and the result of each dependency check.
+type ReadinessResponse struct {
+	Status     string                     `json:"status" example:"ready"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+type ComponentHealth struct {
+	Status    string `json:"status" example:"up"`
+	LatencyMs int64  `json:"latency_ms" example:"3"`
+	Error     string `json:"error,omitempty"`
+	Details   any    `json:"details,omitempty"`
+}
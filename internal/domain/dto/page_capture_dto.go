@@ -1,23 +1,187 @@
 package dto
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/imaging"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/markdown"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/rod"
 	"github.com/google/uuid"
 )
 
+// PageCaptureRequest describes a capture of exactly one source: a URL, raw
+// HTML or Markdown. BaseUrl resolves relative asset links in HTML and Markdown.
+// FailOnHttpError rejects targets answering 4xx/5xx instead of capturing their
+// error page. TimeoutMs bounds each capture and may not exceed the server's
+// maximum.
 type PageCaptureRequest struct {
-	Url          string `json:"url" validate:"required,url"`
-	Width        int    `json:"width,omitempty"`
-	Height       int    `json:"height,omitempty"`
-	FullPage     bool   `json:"fullPage,omitempty"`
-	DelaySeconds int    `json:"delaySeconds,omitempty"`
-	IsMobile     bool   `json:"isMobile,omitempty"`
+	Url             string                `json:"url,omitempty" validate:"omitempty,url"`
+	Html            string                `json:"html,omitempty" validate:"max=5242880"`
+	Markdown        string                `json:"markdown,omitempty" validate:"max=1048576"`
+	BaseUrl         string                `json:"baseUrl,omitempty" validate:"omitempty,url"`
+	Width           int                   `json:"width,omitempty"`
+	Height          int                   `json:"height,omitempty"`
+	FullPage        bool                  `json:"fullPage,omitempty"`
+	DelaySeconds    int                   `json:"delaySeconds,omitempty"`
+	IsMobile        bool                  `json:"isMobile,omitempty"`
+	CacheTtl        int                   `json:"cacheTtl,omitempty" validate:"gte=0,lte=604800"`
+	ForceRefresh    bool                  `json:"forceRefresh,omitempty"`
+	Archive         *PageCaptureArchive   `json:"archive,omitempty"`
+	Debug           bool                  `json:"debug,omitempty"`
+	Trace           bool                  `json:"trace,omitempty"`
+	Transform       *PageCaptureTransform `json:"transform,omitempty"`
+	Actions         []PageCaptureAction   `json:"actions,omitempty" validate:"max=20,dive"`
+	Viewports       []PageCaptureViewport `json:"viewports,omitempty" validate:"max=10,dive"`
+	Output          string                `json:"output,omitempty" validate:"omitempty,oneof=zip sheet batch" example:"zip"`
+	Proxy           string                `json:"proxy,omitempty" validate:"max=64" example:"us"`
+	FailOnHttpError bool                  `json:"failOnHttpError,omitempty"`
+	TimeoutMs       int                   `json:"timeoutMs,omitempty" validate:"gte=0" example:"30000"`
+}
+
+// PageCaptureViewport overrides the request's width, height and isMobile for
+// one capture of a multi-viewport batch.
+type PageCaptureViewport struct {
+	Name     string `json:"name,omitempty" validate:"max=50" example:"desktop"`
+	Width    int    `json:"width" validate:"required,gt=0,lte=8192" example:"1920"`
+	Height   int    `json:"height" validate:"required,gt=0,lte=8192" example:"1080"`
+	IsMobile bool   `json:"isMobile,omitempty"`
+}
+
+const (
+	PageCaptureOutputZip   = "zip"
+	PageCaptureOutputSheet = "sheet"
+	PageCaptureOutputBatch = "batch"
+)
+
+// PageCaptureAction is a browser step run in order before the capture, such
+// as dismissing a dialog or opening a tab. TimeoutMs bounds the step and
+// defaults to 10 seconds.
+type PageCaptureAction struct {
+	Type      string `json:"type" validate:"required,oneof=click type press scroll hover select waitFor wait" example:"click"`
+	Selector  string `json:"selector,omitempty" validate:"max=1024" example:"#accept-cookies"`
+	Text      string `json:"text,omitempty" validate:"max=10000"`
+	Key       string `json:"key,omitempty" validate:"max=32" example:"Enter"`
+	Value     string `json:"value,omitempty" validate:"max=1024"`
+	Ms        int    `json:"ms,omitempty" validate:"gte=0,lte=30000"`
+	TimeoutMs int    `json:"timeoutMs,omitempty" validate:"gte=0,lte=60000"`
+}
+
+// PageCaptureTransform post-processes the captured image: crop, resize,
+// watermark, border and rounded corners are applied in that order, and a
+// thumbnail is stored for each requested width.
+type PageCaptureTransform struct {
+	Resize     *TransformResize    `json:"resize,omitempty"`
+	Crop       *TransformCrop      `json:"crop,omitempty"`
+	Thumbnails []int               `json:"thumbnails,omitempty" validate:"max=5,unique,dive,gte=16,lte=2048"`
+	Watermark  *TransformWatermark `json:"watermark,omitempty"`
+	Border     *TransformBorder    `json:"border,omitempty"`
+	Radius     int                 `json:"radius,omitempty" validate:"gte=0,lte=1024"`
+}
+
+type TransformResize struct {
+	Width  int    `json:"width,omitempty" validate:"gte=0,lte=8192"`
+	Height int    `json:"height,omitempty" validate:"gte=0,lte=8192"`
+	Mode   string `json:"mode,omitempty" validate:"omitempty,oneof=fit fill cover" example:"fit"`
+}
+
+type TransformCrop struct {
+	X      int `json:"x" validate:"gte=0"`
+	Y      int `json:"y" validate:"gte=0"`
+	Width  int `json:"width" validate:"required,gt=0"`
+	Height int `json:"height" validate:"required,gt=0"`
+}
+
+type TransformWatermark struct {
+	Text     string  `json:"text" validate:"required,max=200"`
+	Position string  `json:"position,omitempty" validate:"omitempty,oneof=top-left top-right bottom-left bottom-right center" example:"bottom-right"`
+	Opacity  float64 `json:"opacity,omitempty" validate:"gte=0,lte=1"`
+	Size     int     `json:"size,omitempty" validate:"gte=0,lte=1024"`
+	Color    string  `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#ffffff"`
+}
+
+type TransformBorder struct {
+	Width int    `json:"width" validate:"required,gt=0,lte=256"`
+	Color string `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#000000"`
+}
+
+// PageCaptureArchive selects the document snapshots stored with the capture.
+type PageCaptureArchive struct {
+	Html  bool `json:"html,omitempty"`
+	Mhtml bool `json:"mhtml,omitempty"`
+	Text  bool `json:"text,omitempty"`
 }
 
+// PageRecordingRequest records a page load as an animated GIF. WebP output is
+// not offered because Go has no pure-Go animated WebP encoder.
+type PageRecordingRequest struct {
+	Url             string `json:"url" validate:"required,url"`
+	Width           int    `json:"width,omitempty"`
+	Height          int    `json:"height,omitempty"`
+	IsMobile        bool   `json:"isMobile,omitempty"`
+	DurationSeconds int    `json:"durationSeconds,omitempty" validate:"gte=0,lte=30"`
+	Fps             int    `json:"fps,omitempty" validate:"gte=0,lte=15"`
+	AutoScroll      bool   `json:"autoScroll,omitempty"`
+	Format          string `json:"format,omitempty" validate:"omitempty,oneof=gif"`
+}
+
+const (
+	DefaultRecordingSeconds = 5
+	DefaultRecordingFps     = 5
+)
+
+// PageCaptureResponse carries the captured file. Multi-viewport captures also
+// set Batch, and leave Content empty when the batch output was requested.
 type PageCaptureResponse struct {
-	Filename string `json:"filename"`
-	Content  []byte `json:"content"`
+	Filename    string                    `json:"filename"`
+	ContentType string                    `json:"content_type"`
+	Content     []byte                    `json:"content"`
+	CacheHit    bool                      `json:"cache_hit"`
+	CaptureID   uuid.UUID                 `json:"capture_id"`
+	Metadata    rod.PageMetadata          `json:"metadata"`
+	Batch       *PageCaptureBatchResponse `json:"batch,omitempty"`
+}
+
+type PageCaptureBatchResponse struct {
+	BatchID  uuid.UUID              `json:"batch_id"`
+	Captures []PageCaptureBatchItem `json:"captures"`
+}
+
+type PageCaptureBatchItem struct {
+	CaptureID uuid.UUID `json:"capture_id"`
+	Name      string    `json:"name"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	IsMobile  bool      `json:"is_mobile"`
+}
+
+type PageCaptureFile struct {
+	Filename    string
+	ContentType string
+	Content     io.ReadCloser
+}
+
+type SignedUrlResponse struct {
+	Url       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type DeletePageCapturesRequest struct {
+	Ids []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+type DeletePageCapturesResponse struct {
+	Deleted int `json:"deleted"`
 }
 
 type PagesCaptureResponse struct {
@@ -26,17 +190,290 @@ type PagesCaptureResponse struct {
 	Page       int                  `json:"page"`
 	Limit      int                  `json:"limit"`
 	TotalPages int                  `json:"total_pages"`
+	NextCursor string               `json:"next_cursor,omitempty"`
 }
 
-func ConvertToScreenshotOptions(req *PageCaptureRequest) *rod.ScreenshotOptions {
-	return &rod.ScreenshotOptions{
-		URL:          req.Url,
-		Width:        req.Width,
-		Height:       req.Height,
-		FullPage:     req.FullPage,
-		DelaySeconds: req.DelaySeconds,
-		IsMobile:     req.IsMobile,
+const (
+	DefaultPageCaptureLimit = 10
+	MaxPageCaptureLimit     = 100
+)
+
+// PageCaptureSortFields lists the fields history can be ordered by.
+var PageCaptureSortFields = []string{"created_at", "updated_at", "url", "domain", "width", "height"}
+
+// PageCaptureQuery filters and paginates a user's capture history. When Cursor
+// is set it takes precedence over Page.
+type PageCaptureQuery struct {
+	Search     string
+	OrderBy    string
+	Sort       string
+	Page       int
+	Limit      int
+	Cursor     string
+	From       *time.Time
+	To         *time.Time
+	Domain     string
+	Format     string
+	Width      *int
+	Height     *int
+	FullPage   *bool
+	IsMobile   *bool
+	StatusCode *int
+	BatchID    *uuid.UUID
+}
+
+func (q *PageCaptureQuery) Validate() error {
+	if q.OrderBy == "" {
+		q.OrderBy = "created_at"
+	}
+	if !slices.Contains(PageCaptureSortFields, q.OrderBy) {
+		return fmt.Errorf("order_by must be one of: %s", strings.Join(PageCaptureSortFields, ", "))
+	}
+
+	if q.Sort == "" {
+		q.Sort = "desc"
+	}
+	if q.Sort != "asc" && q.Sort != "desc" {
+		return errors.New("sort must be asc or desc")
+	}
+
+	if q.Page < 1 {
+		return errors.New("page must be greater than 0")
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultPageCaptureLimit
+	}
+	if q.Limit < 1 || q.Limit > MaxPageCaptureLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxPageCaptureLimit)
+	}
+
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return errors.New("from must be before to")
+	}
+
+	q.Domain = strings.ToLower(strings.TrimSpace(q.Domain))
+	q.Format = strings.ToLower(strings.TrimSpace(q.Format))
+
+	return nil
+}
+
+func ConvertToScreenshotOptions(req *PageCaptureRequest) (*rod.ScreenshotOptions, error) {
+	opt := &rod.ScreenshotOptions{
+		URL:             req.Url,
+		HTML:            req.Html,
+		BaseURL:         req.BaseUrl,
+		Width:           req.Width,
+		Height:          req.Height,
+		FullPage:        req.FullPage,
+		DelaySeconds:    req.DelaySeconds,
+		IsMobile:        req.IsMobile,
+		Debug:           req.Debug,
+		Trace:           req.Trace,
+		FailOnHTTPError: req.FailOnHttpError,
+		Timeout:         time.Duration(req.TimeoutMs) * time.Millisecond,
+	}
+
+	if opt.Timeout > rod.MaxCaptureTimeout() {
+		return nil, fmt.Errorf("timeoutMs must not exceed %d", rod.MaxCaptureTimeout().Milliseconds())
+	}
+
+	if req.Proxy != "" {
+		proxy, ok := rod.LookupProxy(req.Proxy)
+		if !ok {
+			return nil, errorEntity.ErrUnknownProxy
+		}
+		opt.Proxy = proxy
+	}
+
+	for _, action := range req.Actions {
+		opt.Actions = append(opt.Actions, rod.Action{
+			Type:     action.Type,
+			Selector: action.Selector,
+			Text:     action.Text,
+			Key:      action.Key,
+			Value:    action.Value,
+			Duration: time.Duration(action.Ms) * time.Millisecond,
+			Timeout:  time.Duration(action.TimeoutMs) * time.Millisecond,
+		})
+	}
+
+	if req.Archive != nil {
+		opt.Archive = rod.ArchiveOptions{
+			HTML:  req.Archive.Html,
+			MHTML: req.Archive.Mhtml,
+			Text:  req.Archive.Text,
+		}
+	}
+
+	if req.Markdown != "" {
+		html, err := markdown.ToHTML(req.Markdown)
+		if err != nil {
+			return nil, err
+		}
+		opt.HTML = html
 	}
+
+	return opt, nil
+}
+
+// ViewportName labels a batch capture, falling back to its size.
+func (v PageCaptureViewport) ViewportName() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return fmt.Sprintf("%dx%d", v.Width, v.Height)
+}
+
+// ApplyMetadata copies the page metadata collected during capture onto the
+// history row.
+func ApplyMetadata(capture *entity.PageCapture, metadata rod.PageMetadata) {
+	capture.Title = metadata.Title
+	capture.Description = metadata.Description
+	capture.OpenGraph = metadata.OpenGraph
+	capture.TwitterCard = metadata.Twitter
+	capture.CanonicalURL = metadata.CanonicalURL
+	capture.FinalURL = metadata.FinalURL
+	capture.StatusCode = metadata.StatusCode
+	capture.Favicon = metadata.Favicon
+}
+
+const (
+	PageCaptureSourceUrl      = "url"
+	PageCaptureSourceHtml     = "html"
+	PageCaptureSourceMarkdown = "markdown"
+)
+
+// Source returns which of url, html or markdown the request captures.
+func (r PageCaptureRequest) Source() string {
+	switch {
+	case r.Html != "":
+		return PageCaptureSourceHtml
+	case r.Markdown != "":
+		return PageCaptureSourceMarkdown
+	default:
+		return PageCaptureSourceUrl
+	}
+}
+
+func (r PageCaptureRequest) Validate() error {
+	sources := 0
+	for _, source := range []string{r.Url, r.Html, r.Markdown} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("exactly one of url, html or markdown is required")
+	}
+
+	if r.BaseUrl != "" && r.Url != "" {
+		return errors.New("baseUrl can only be used with html or markdown")
+	}
+
+	if r.Transform != nil && r.Transform.Resize != nil && r.Transform.Resize.Width == 0 && r.Transform.Resize.Height == 0 {
+		return errors.New("transform resize requires width or height")
+	}
+
+	if r.Output != "" && len(r.Viewports) == 0 {
+		return errors.New("output requires viewports")
+	}
+
+	for i, action := range r.Actions {
+		if err := action.validate(); err != nil {
+			return fmt.Errorf("actions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (a PageCaptureAction) validate() error {
+	switch a.Type {
+	case rod.ActionPress:
+		if _, err := rod.ParseKey(a.Key); err != nil {
+			return err
+		}
+	case rod.ActionWait:
+		if a.Ms <= 0 {
+			return errors.New("wait requires ms")
+		}
+	default:
+		if a.Selector == "" {
+			return fmt.Errorf("%s requires selector", a.Type)
+		}
+	}
+
+	if a.Type == rod.ActionType && a.Text == "" {
+		return errors.New("type requires text")
+	}
+	if a.Type == rod.ActionSelect && a.Value == "" {
+		return errors.New("select requires value")
+	}
+	return nil
+}
+
+func ConvertToTransformOptions(t *PageCaptureTransform) imaging.Options {
+	opt := imaging.Options{
+		Radius:     t.Radius,
+		Thumbnails: t.Thumbnails,
+	}
+
+	if t.Crop != nil {
+		opt.Crop = &imaging.Crop{X: t.Crop.X, Y: t.Crop.Y, Width: t.Crop.Width, Height: t.Crop.Height}
+	}
+
+	if t.Resize != nil {
+		opt.Resize = &imaging.Resize{Width: t.Resize.Width, Height: t.Resize.Height, Mode: t.Resize.Mode}
+	}
+
+	if t.Watermark != nil {
+		opt.Watermark = &imaging.Watermark{
+			Text:     t.Watermark.Text,
+			Position: t.Watermark.Position,
+			Opacity:  t.Watermark.Opacity,
+			Size:     t.Watermark.Size,
+			Color:    t.Watermark.Color,
+		}
+	}
+
+	if t.Border != nil {
+		opt.Border = &imaging.Border{Width: t.Border.Width, Color: t.Border.Color}
+	}
+
+	return opt
+}
+
+func ConvertToRecordingOptions(req *PageRecordingRequest) *rod.RecordingOptions {
+	duration := req.DurationSeconds
+	if duration == 0 {
+		duration = DefaultRecordingSeconds
+	}
+
+	fps := req.Fps
+	if fps == 0 {
+		fps = DefaultRecordingFps
+	}
+
+	return &rod.RecordingOptions{
+		URL:        req.Url,
+		Width:      req.Width,
+		Height:     req.Height,
+		IsMobile:   req.IsMobile,
+		Duration:   time.Duration(duration) * time.Second,
+		Fps:        fps,
+		AutoScroll: req.AutoScroll,
+	}
+}
+
+func ConvertRecordingRequestToEntity(req PageRecordingRequest, userID uuid.UUID) *entity.PageCapture {
+	capture := ConvertRequestToEntity(PageCaptureRequest{
+		Url:      req.Url,
+		Width:    req.Width,
+		Height:   req.Height,
+		IsMobile: req.IsMobile,
+	}, userID)
+	capture.Format = "gif"
+	return capture
 }
 
 func ConvertRequestToEntity(req PageCaptureRequest, userID uuid.UUID) *entity.PageCapture {
@@ -50,10 +487,68 @@ func ConvertRequestToEntity(req PageCaptureRequest, userID uuid.UUID) *entity.Pa
 	return &entity.PageCapture{
 		UserID:       userID,
 		URL:          req.Url,
+		Source:       req.Source(),
+		Domain:       domainOf(req.Url),
+		Format:       "png",
 		Width:        intPtr(req.Width),
 		Height:       intPtr(req.Height),
 		FullPage:     req.FullPage,
 		DelaySeconds: req.DelaySeconds,
 		IsMobile:     req.IsMobile,
+		Proxy:        req.Proxy,
+	}
+}
+
+// HasArtifacts reports whether the capture produces archive snapshots or
+// thumbnails besides the image. Those are only stored with the capture, so
+// such requests are not served from the cache.
+func (r PageCaptureRequest) HasArtifacts() bool {
+	archive := r.Archive != nil && (r.Archive.Html || r.Archive.Mhtml || r.Archive.Text)
+	thumbnails := r.Transform != nil && len(r.Transform.Thumbnails) > 0
+	return archive || thumbnails
+}
+
+// CacheKey returns a stable hash of the options that affect the rendered image.
+// Cache controls are excluded and the URL scheme and host are lower-cased so
+// equivalent requests share the same entry.
+func (r PageCaptureRequest) CacheKey() (string, error) {
+	normalized := r
+	normalized.CacheTtl = 0
+	normalized.ForceRefresh = false
+	if r.Url != "" {
+		normalized.Url = normalizeUrl(r.Url)
+	}
+
+	raw, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func normalizeUrl(raw string) string {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+	parsed.Fragment = ""
+
+	return parsed.String()
+}
+
+func domainOf(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
 	}
+	return strings.ToLower(parsed.Hostname())
 }
@@ -0,0 +1,31 @@
+package dto
+
+type CreateTemplateRequest struct {
+	Name        string `json:"name" validate:"required,max=100" example:"Social card"`
+	Description string `json:"description,omitempty" validate:"max=500"`
+	Content     string `json:"content" validate:"required,max=1048576" example:"<h1>{{.title}}</h1>"`
+	Width       int    `json:"width,omitempty" validate:"gte=0,lte=8192" example:"1200"`
+	Height      int    `json:"height,omitempty" validate:"gte=0,lte=8192" example:"630"`
+}
+
+// UpdateTemplateRequest changes only the fields that are set.
+type UpdateTemplateRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	Content     *string `json:"content,omitempty" validate:"omitempty,min=1,max=1048576"`
+	Width       *int    `json:"width,omitempty" validate:"omitempty,gte=0,lte=8192"`
+	Height      *int    `json:"height,omitempty" validate:"omitempty,gte=0,lte=8192"`
+}
+
+type RenderTemplateRequest struct {
+	Variables map[string]any `json:"variables"`
+	Format    string         `json:"format,omitempty" validate:"omitempty,oneof=png pdf" example:"png"`
+	FullPage  bool           `json:"fullPage,omitempty"`
+}
+
+type RenderTemplateResponse struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	CacheHit    bool
+}
@@ -21,6 +21,9 @@ type UserResponse struct {
 	EmailVerified  *time.Time `json:"email_verified"`
 	CreatedAt      *time.Time `json:"created_at"`
 	UpdatedAt      *time.Time `json:"updated_at"`
+
+	RetentionDays        *int `json:"retention_days"`
+	RetentionMaxCaptures *int `json:"retention_max_captures"`
 }
 
 func ToUserResponse(user entity.User) *UserResponse {
@@ -32,6 +35,9 @@ func ToUserResponse(user entity.User) *UserResponse {
 		EmailVerified:  user.EmailVerified,
 		CreatedAt:      &user.CreatedAt,
 		UpdatedAt:      &user.UpdatedAt,
+
+		RetentionDays:        user.RetentionDays,
+		RetentionMaxCaptures: user.RetentionMaxCaptures,
 	}
 }
 
@@ -49,6 +55,13 @@ type DeleteRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// UpdateRetentionRequest replaces the user's retention policy. Omitted or null
+// fields disable that rule.
+type UpdateRetentionRequest struct {
+	RetentionDays        *int `json:"retention_days" binding:"omitempty,gte=1,lte=3650" example:"30"`
+	RetentionMaxCaptures *int `json:"retention_max_captures" binding:"omitempty,gte=1,lte=100000" example:"100"`
+}
+
 func (r UpdateUserProfileRequest) Validate() error {
 	if r.ProfilePicture == nil {
 		return errors.New("profile_picture is required")
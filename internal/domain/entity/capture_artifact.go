@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/entity"
+	"github.com/google/uuid"
+)
+
+// CaptureArtifact is a file stored alongside a page capture, such as an HTML
+// or MHTML snapshot. Its Status follows the PageCaptureStatus values.
+type CaptureArtifact struct {
+	entity.Entity
+	CaptureID   uuid.UUID `json:"capture_id" gorm:"type:uuid;not null;index"`
+	Kind        string    `json:"kind" gorm:"not null"`
+	Filename    string    `json:"filename" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"not null"`
+	Size        int       `json:"size"`
+	PublicId    string    `json:"-"`
+	Status      string    `json:"status" gorm:"not null;default:pending;index"`
+}
+
+func NewCaptureArtifact(captureID uuid.UUID, kind string, filename string, contentType string, size int) *CaptureArtifact {
+	return &CaptureArtifact{
+		Entity:      entity.Entity{UUID: uuid.New()},
+		CaptureID:   captureID,
+		Kind:        kind,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		Status:      PageCaptureStatusPending,
+	}
+}
+
+func (a *CaptureArtifact) TableName() string {
+	return "capture_artifacts"
+}
@@ -5,17 +5,41 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	PageCaptureStatusPending = "pending"
+	PageCaptureStatusStored  = "stored"
+	PageCaptureStatusFailed  = "failed"
+)
+
 type PageCapture struct {
 	entity.Entity
-	UserID       uuid.UUID `json:"user_id" gorm:"not null"`
-	URL          string    `json:"url"`
-	ImagePath    string    `json:"image_path"`
-	PublicId     string    `json:"public_id"`
-	Width        *int      `json:"width,omitempty"`
-	Height       *int      `json:"height,omitempty"`
-	FullPage     bool      `json:"full_page"`
-	DelaySeconds int       `json:"delay_seconds"`
-	IsMobile     bool      `json:"is_mobile"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"not null"`
+	URL          string     `json:"url"`
+	Source       string     `json:"source" gorm:"not null;default:url"`
+	Domain       string     `json:"domain" gorm:"index"`
+	Format       string     `json:"format" gorm:"not null;default:png"`
+	ImagePath    string     `json:"-"`
+	PublicId     string     `json:"-"`
+	ContentType  string     `json:"content_type" gorm:"default:image/png"`
+	Width        *int       `json:"width,omitempty"`
+	Height       *int       `json:"height,omitempty"`
+	FullPage     bool       `json:"full_page"`
+	DelaySeconds int        `json:"delay_seconds"`
+	IsMobile     bool       `json:"is_mobile"`
+	Proxy        string     `json:"proxy,omitempty"`
+	Status       string     `json:"status" gorm:"not null;default:stored;index"`
+	BatchID      *uuid.UUID `json:"batch_id,omitempty" gorm:"type:uuid;index"`
+
+	Title        string            `json:"title"`
+	Description  string            `json:"description"`
+	OpenGraph    map[string]string `json:"open_graph,omitempty" gorm:"type:jsonb;serializer:json"`
+	TwitterCard  map[string]string `json:"twitter_card,omitempty" gorm:"type:jsonb;serializer:json"`
+	CanonicalURL string            `json:"canonical_url"`
+	FinalURL     string            `json:"final_url"`
+	StatusCode   int               `json:"status_code" gorm:"index"`
+	Favicon      string            `json:"favicon"`
+
+	Artifacts []CaptureArtifact `json:"artifacts,omitempty" gorm:"foreignKey:CaptureID;references:UUID"`
 }
 
 func NewPageCapture(userID uuid.UUID, url string, imagePath string, publicId string, width *int, height *int, fullPage bool, delaySeconds int, IsMobile bool) (*PageCapture, error) {
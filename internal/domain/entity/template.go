@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/entity"
+	"github.com/google/uuid"
+)
+
+// Template is a user-owned HTML/CSS document with html/template placeholders
+// such as {{.title}}. Version is bumped whenever the rendered output can
+// change, which invalidates cached renders.
+type Template struct {
+	entity.Entity
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	Content     string    `json:"content" gorm:"type:text;not null"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Version     int       `json:"version" gorm:"not null;default:1"`
+}
+
+func NewTemplate(userID uuid.UUID, name string, description string, content string, width int, height int) *Template {
+	return &Template{
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+		Content:     content,
+		Width:       width,
+		Height:      height,
+		Version:     1,
+	}
+}
+
+func (t *Template) TableName() string {
+	return "templates"
+}
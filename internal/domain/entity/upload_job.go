@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	UploadJobStatusPending = "pending"
+	UploadJobStatusDead    = "dead"
+)
+
+// UploadJob is an outbox entry holding capture bytes until they are stored.
+// Jobs with an ArtifactID upload one of the capture's artifacts instead of
+// its image. TraceParent links the upload's span to the request that queued
+// it.
+type UploadJob struct {
+	entity.Entity
+	CaptureID     uuid.UUID  `json:"capture_id" gorm:"type:uuid;not null;index"`
+	ArtifactID    *uuid.UUID `json:"artifact_id,omitempty" gorm:"type:uuid;index"`
+	Key           string     `json:"key" gorm:"not null"`
+	ContentType   string     `json:"content_type" gorm:"not null"`
+	Payload       []byte     `json:"-" gorm:"type:bytea;not null"`
+	Status        string     `json:"status" gorm:"not null;default:pending;index"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	LastError     string     `json:"last_error"`
+	TraceParent   string     `json:"-"`
+}
+
+func NewUploadJob(captureID uuid.UUID, key string, contentType string, payload []byte) *UploadJob {
+	return &UploadJob{
+		CaptureID:     captureID,
+		Key:           key,
+		ContentType:   contentType,
+		Payload:       payload,
+		Status:        UploadJobStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+func (u *UploadJob) TableName() string {
+	return "upload_jobs"
+}
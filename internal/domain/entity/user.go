@@ -13,6 +13,10 @@ type User struct {
 	ProfilePicture string     `json:"profile_picture"`
 	PublicId       string     `json:"public_id"`
 	EmailVerified  *time.Time `json:"email_verified"`
+	// RetentionDays removes captures older than the given number of days
+	RetentionDays *int `json:"retention_days"`
+	// RetentionMaxCaptures keeps only the given number of most recent captures
+	RetentionMaxCaptures *int `json:"retention_max_captures"`
 }
 
 func NewUser(email string, password string, name string, profilePicture string) (*User, error) {
@@ -23,6 +23,7 @@ var (
 	ErrLimitExceeded          = errors.New("limit exceeded")
 	ErrEmailNotVerified       = errors.New("email not verified")
 	ErrPasswordNotSet         = errors.New("password not set")
+	ErrAdminRequired          = errors.New("admin access required")
 )
 
 // Request validation errors
@@ -30,6 +31,7 @@ var (
 	ErrRequestBodyRequired = errors.New("invalid request: request body is required")
 	ErrInvalidRequestBody  = errors.New("invalid request body")
 	ErrInvalidRequest      = fmt.Errorf("invalid request")
+	ErrUnknownProxy        = errors.New("unknown proxy")
 )
 
 // Token Error
@@ -39,10 +41,29 @@ var (
 	ErrTokenExpired            = errors.New("token has expired")
 	ErrTokenMismatch           = errors.New("token hash mismatch")
 	ErrTokenAlreadyBlacklisted = errors.New("token already blacklisted")
+	ErrInvalidSignature        = errors.New("invalid signature")
+	ErrSignatureExpired        = errors.New("signature has expired")
+	ErrSigningNotConfigured    = errors.New("url signing is not configured")
 )
 
 var (
 	ErrCloudinaryUpload = fmt.Errorf("cloudinary upload error")
 	ErrImageTooLarge    = fmt.Errorf("image too large")
 	ErrDataNotFound     = fmt.Errorf("data not found")
+	ErrCaptureNotStored = errors.New("page capture is not stored yet")
+	ErrCaptureAction    = errors.New("capture action failed")
+)
+
+// Capture errors classify why the browser could not capture a page, so
+// clients can tell bad requests from failures worth retrying.
+var (
+	ErrCaptureInvalidTarget  = errors.New("target url cannot be loaded")
+	ErrCaptureTargetStatus   = errors.New("target responded with an http error")
+	ErrCaptureDNS            = errors.New("target host could not be resolved")
+	ErrCaptureTLS            = errors.New("target tls handshake failed")
+	ErrCaptureConnection     = errors.New("could not connect to target")
+	ErrCaptureNavigation     = errors.New("navigation failed")
+	ErrCaptureTimeout        = errors.New("capture timed out")
+	ErrCaptureBrowserCrashed = errors.New("browser crashed or is unavailable")
+	ErrCaptureCanceled       = errors.New("capture canceled by the client")
 )
@@ -0,0 +1,220 @@
+package cloudinary
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	storageContract "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/storage"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/cloudinary/cloudinary-go/v2/asset"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ storageContract.Service = (*Service)(nil)
+
+// pingTTL is how long a Ping result is reused. Ping calls the Admin API,
+// which is rate limited per hour, and readiness probes run every few seconds.
+const pingTTL = 30 * time.Second
+
+type Service struct {
+	cld    *cloudinary.Cloudinary
+	client *http.Client
+
+	pingMu  sync.Mutex
+	pingAt  time.Time
+	pingErr error
+}
+
+func NewStorageService(cld *cloudinary.Cloudinary) *Service {
+	return &Service{
+		cld:    cld,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (s *Service) Upload(ctx context.Context, key string, data []byte, contentType string) (*storageContract.Object, error) {
+	ctx, span := tracing.Start(ctx, "cloudinary.Upload", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("storage.key", key),
+		attribute.String("storage.content_type", contentType),
+		attribute.Int("storage.size", len(data)),
+	))
+	object, err := s.upload(ctx, key, data, contentType)
+	tracing.End(span, err)
+	return object, err
+}
+
+func (s *Service) upload(ctx context.Context, key string, data []byte, contentType string) (*storageContract.Object, error) {
+	overwrite := true
+	result, err := s.cld.Upload.Upload(ctx, bytes.NewReader(data), uploader.UploadParams{
+		PublicID:     key,
+		Overwrite:    &overwrite,
+		ResourceType: resourceType(contentType),
+		Type:         api.Authenticated,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errorEntity.ErrCloudinaryUpload, err)
+	}
+
+	if result.Error.Message != "" {
+		return nil, fmt.Errorf("%w: %s", errorEntity.ErrCloudinaryUpload, result.Error.Message)
+	}
+
+	return &storageContract.Object{
+		Key:         key,
+		URL:         result.SecureURL,
+		ContentType: contentType,
+	}, nil
+}
+
+// Open fetches the object through a signed delivery URL, which is never
+// handed out. Objects uploaded before captures were stored as authenticated
+// assets are still public, so a miss falls back to the public delivery type.
+func (s *Service) Open(ctx context.Context, key string, contentType string) (io.ReadCloser, error) {
+	body, err := s.open(ctx, key, contentType, api.Authenticated)
+	if errors.Is(err, errorEntity.ErrDataNotFound) {
+		return s.open(ctx, key, contentType, api.Upload)
+	}
+	return body, err
+}
+
+func (s *Service) open(ctx context.Context, key string, contentType string, deliveryType api.DeliveryType) (io.ReadCloser, error) {
+	a, err := s.asset(key, contentType, deliveryType)
+	if err != nil {
+		return nil, err
+	}
+
+	assetURL, err := a.String()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build asset url: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, assetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch asset: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, errorEntity.ErrDataNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch asset: unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// Delete removes the authenticated object under key, or the public one when
+// there is none, as with profile pictures and captures stored before uploads
+// became authenticated.
+func (s *Service) Delete(ctx context.Context, key string, contentType string) error {
+	found, err := s.destroy(ctx, key, contentType, api.Authenticated)
+	if err != nil || found {
+		return err
+	}
+
+	_, err = s.destroy(ctx, key, contentType, api.Upload)
+	return err
+}
+
+func (s *Service) destroy(ctx context.Context, key string, contentType string, deliveryType api.DeliveryType) (bool, error) {
+	result, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     key,
+		Type:         string(deliveryType),
+		ResourceType: resourceType(contentType),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if result.Error.Message != "" {
+		return false, fmt.Errorf("failed to delete asset: %s", result.Error.Message)
+	}
+
+	return result.Result != "not found", nil
+}
+
+// Ping checks the Cloudinary credentials and reachability. The result is
+// cached for pingTTL, and concurrent callers share a single request.
+func (s *Service) Ping(ctx context.Context) error {
+	s.pingMu.Lock()
+	defer s.pingMu.Unlock()
+
+	if !s.pingAt.IsZero() && time.Since(s.pingAt) < pingTTL {
+		return s.pingErr
+	}
+
+	err := s.ping(ctx)
+	if ctx.Err() != nil {
+		// The caller gave up, which says nothing about Cloudinary.
+		return err
+	}
+	s.pingAt, s.pingErr = time.Now(), err
+	return err
+}
+
+func (s *Service) ping(ctx context.Context) error {
+	result, err := s.cld.Admin.Ping(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.Error.Message != "" {
+		return fmt.Errorf("cloudinary ping failed: %s", result.Error.Message)
+	}
+
+	return nil
+}
+
+// asset builds the delivery URL for key. Authenticated assets are only
+// served with a URL signed with the API secret.
+func (s *Service) asset(key string, contentType string, deliveryType api.DeliveryType) (*asset.Asset, error) {
+	var a *asset.Asset
+	var err error
+	switch resourceType(contentType) {
+	case "image":
+		a, err = s.cld.Image(key)
+	case "video":
+		a, err = s.cld.Video(key)
+	default:
+		a, err = s.cld.File(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.DeliveryType = deliveryType
+	a.Config.URL.SignURL = deliveryType == api.Authenticated
+	return a, nil
+}
+
+// resourceType maps a MIME type onto the Cloudinary resource type it is stored as.
+func resourceType(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return "image"
+	case strings.HasPrefix(contentType, "video/"):
+		return "video"
+	default:
+		return "raw"
+	}
+}
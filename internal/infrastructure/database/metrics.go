@@ -0,0 +1,34 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"gorm.io/gorm"
+)
+
+// registerErrorMetrics counts failed statements. Record not found is left out
+// because callers treat it as a normal result.
+func registerErrorMetrics(db *gorm.DB) error {
+	count := func(tx *gorm.DB) {
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			metrics.DependencyErrors.WithLabelValues(metrics.DependencyPostgres).Inc()
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().After("gorm:create").Register("metrics:create", count),
+		cb.Query().After("gorm:query").Register("metrics:query", count),
+		cb.Update().After("gorm:update").Register("metrics:update", count),
+		cb.Delete().After("gorm:delete").Register("metrics:delete", count),
+		cb.Row().After("gorm:row").Register("metrics:row", count),
+		cb.Raw().After("gorm:raw").Register("metrics:raw", count),
+	} {
+		if err != nil {
+			return fmt.Errorf("failed to register database metrics: %w", err)
+		}
+	}
+	return nil
+}
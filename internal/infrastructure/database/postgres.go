@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
@@ -40,12 +41,26 @@ func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
 
 	db, err := gorm.Open(postgres.Open(dbURL), &gorm.Config{
 		PrepareStmt: true,
-		Logger:      logger.Default.LogMode(logger.Info),
+		// Queries go through logrus, so they share its format, without
+		// bound values that could hold passwords or tokens.
+		Logger: logger.New(logrus.StandardLogger(), logger.Config{
+			SlowThreshold:        200 * time.Millisecond,
+			LogLevel:             logger.Info,
+			ParameterizedQueries: true,
+		}),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
 
+	if err := registerErrorMetrics(db); err != nil {
+		return nil, err
+	}
+
+	if err := registerTracing(db); err != nil {
+		return nil, err
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
@@ -72,6 +87,9 @@ func Migrate(db *gorm.DB) error {
 	err := db.Migrator().DropTable(
 		&entity.User{},
 		&entity.PageCapture{},
+		&entity.CaptureArtifact{},
+		&entity.UploadJob{},
+		&entity.Template{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to drop tables: %w", err)
@@ -80,6 +98,9 @@ func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(
 		&entity.User{},
 		&entity.PageCapture{},
+		&entity.CaptureArtifact{},
+		&entity.UploadJob{},
+		&entity.Template{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -87,3 +108,12 @@ func Migrate(db *gorm.DB) error {
 	logrus.Info("Database migrations completed successfully")
 	return nil
 }
+
+// Ping checks the connection pool can reach Postgres.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,441 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	ResizeFit   = "fit"
+	ResizeFill  = "fill"
+	ResizeCover = "cover"
+)
+
+const (
+	PositionTopLeft     = "top-left"
+	PositionTopRight    = "top-right"
+	PositionBottomLeft  = "bottom-left"
+	PositionBottomRight = "bottom-right"
+	PositionCenter      = "center"
+)
+
+var ErrEmptyCrop = errors.New("crop area does not intersect the image")
+
+// Options describes the post-processing applied to a captured image. Steps
+// run in a fixed order: crop, resize, watermark, border, rounded corners.
+// Thumbnails are generated from the final image.
+type Options struct {
+	Crop       *Crop
+	Resize     *Resize
+	Watermark  *Watermark
+	Border     *Border
+	Radius     int
+	Thumbnails []int
+}
+
+type Crop struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// Resize scales the image into Width x Height. "fit" keeps the aspect ratio
+// inside the box, "fill" stretches to the exact size and "cover" scales to
+// cover the box and crops the overflow around the centre. When only one side
+// is given the other follows the aspect ratio. The output is scaled down
+// further, keeping its shape, to stay within 16 megapixels.
+type Resize struct {
+	Width  int
+	Height int
+	Mode   string
+}
+
+type Watermark struct {
+	Text     string
+	Position string
+	Opacity  float64
+	Size     int
+	Color    string
+}
+
+type Border struct {
+	Width int
+	Color string
+}
+
+type Thumbnail struct {
+	Width int
+	Data  []byte
+}
+
+type Result struct {
+	Image      []byte
+	Thumbnails []Thumbnail
+}
+
+// Transform decodes data, applies opt and re-encodes the result as PNG.
+func Transform(data []byte, opt Options) (*Result, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	img := src
+	if opt.Crop != nil {
+		if img, err = crop(img, *opt.Crop); err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.Resize != nil {
+		img = resize(img, *opt.Resize)
+	}
+
+	if opt.Watermark != nil && opt.Watermark.Text != "" {
+		if img, err = watermark(img, *opt.Watermark); err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.Border != nil && opt.Border.Width > 0 {
+		if img, err = border(img, *opt.Border); err != nil {
+			return nil, err
+		}
+	}
+
+	if opt.Radius > 0 {
+		img = roundCorners(img, opt.Radius)
+	}
+
+	encoded, err := encodePNG(img)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Image: encoded}
+	for _, width := range opt.Thumbnails {
+		thumb, err := encodePNG(resize(img, Resize{Width: width, Mode: ResizeFit}))
+		if err != nil {
+			return nil, err
+		}
+		result.Thumbnails = append(result.Thumbnails, Thumbnail{Width: width, Data: thumb})
+	}
+
+	return result, nil
+}
+
+func crop(img image.Image, c Crop) (image.Image, error) {
+	bounds := img.Bounds()
+	rect := image.Rect(c.X, c.Y, c.X+c.Width, c.Y+c.Height).Add(bounds.Min).Intersect(bounds)
+	if rect.Empty() {
+		return nil, ErrEmptyCrop
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst, nil
+}
+
+func resize(img image.Image, r Resize) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW == 0 || srcH == 0 || (r.Width <= 0 && r.Height <= 0) {
+		return img
+	}
+
+	width, height := r.Width, r.Height
+	if width <= 0 {
+		return scale(img, bounds, max(1, srcW*height/srcH), height)
+	}
+	if height <= 0 {
+		return scale(img, bounds, width, max(1, srcH*width/srcW))
+	}
+
+	switch r.Mode {
+	case ResizeFill:
+		return scale(img, bounds, width, height)
+	case ResizeCover:
+		ratio := max(float64(width)/float64(srcW), float64(height)/float64(srcH))
+		cropW := min(srcW, int(float64(width)/ratio+0.5))
+		cropH := min(srcH, int(float64(height)/ratio+0.5))
+		x := bounds.Min.X + (srcW-cropW)/2
+		y := bounds.Min.Y + (srcH-cropH)/2
+		return scale(img, image.Rect(x, y, x+cropW, y+cropH), width, height)
+	default:
+		ratio := min(float64(width)/float64(srcW), float64(height)/float64(srcH))
+		return scale(img, bounds, max(1, int(float64(srcW)*ratio+0.5)), max(1, int(float64(srcH)*ratio+0.5)))
+	}
+}
+
+// resizeMaxPixels caps a resized image at 16 megapixels, 64MB as RGBA. A
+// width alone on a full-page capture would otherwise scale its height past
+// 100,000px.
+const resizeMaxPixels = 16 << 20
+
+func scale(img image.Image, src image.Rectangle, width int, height int) image.Image {
+	if pixels := float64(width) * float64(height); pixels > resizeMaxPixels {
+		ratio := math.Sqrt(resizeMaxPixels / pixels)
+		width = max(1, int(float64(width)*ratio))
+		height = max(1, int(float64(height)*ratio))
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
+
+// watermark renders the text with the built-in bitmap font and scales it to
+// the requested height, so no font files are needed at runtime. Text that
+// would not fit is scaled down to the image, which also bounds the scaled
+// text's allocation by the image's size.
+func watermark(img image.Image, w Watermark) (image.Image, error) {
+	textColor := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if w.Color != "" {
+		parsed, err := parseHexColor(w.Color)
+		if err != nil {
+			return nil, err
+		}
+		textColor = parsed
+	}
+
+	opacity := w.Opacity
+	if opacity <= 0 {
+		opacity = 0.5
+	}
+
+	face := basicfont.Face7x13
+	textW := font.MeasureString(face, w.Text).Ceil()
+	textH := face.Metrics().Height.Ceil()
+
+	text := image.NewRGBA(image.Rect(0, 0, textW, textH))
+	drawer := &font.Drawer{
+		Dst:  text,
+		Src:  image.NewUniform(textColor),
+		Face: face,
+		Dot:  fixed.P(0, face.Metrics().Ascent.Ceil()),
+	}
+	drawer.DrawString(w.Text)
+
+	bounds := img.Bounds()
+	size := w.Size
+	if size <= 0 {
+		size = max(textH, bounds.Dy()/20)
+	}
+	size = max(1, min(size, bounds.Dy()))
+	scaledW := max(1, textW*size/textH)
+	if scaledW > bounds.Dx() {
+		scaledW = max(1, bounds.Dx())
+		size = max(1, scaledW*textH/textW)
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, scaledW, size))
+	draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), text, text.Bounds(), draw.Src, nil)
+
+	margin := size / 2
+	var at image.Point
+	switch w.Position {
+	case PositionTopLeft:
+		at = image.Pt(margin, margin)
+	case PositionTopRight:
+		at = image.Pt(bounds.Dx()-scaledW-margin, margin)
+	case PositionBottomLeft:
+		at = image.Pt(margin, bounds.Dy()-size-margin)
+	case PositionCenter:
+		at = image.Pt((bounds.Dx()-scaledW)/2, (bounds.Dy()-size)/2)
+	default:
+		at = image.Pt(bounds.Dx()-scaledW-margin, bounds.Dy()-size-margin)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+
+	mask := image.NewUniform(color.Alpha{A: uint8(opacity * 255)})
+	target := scaled.Bounds().Add(at)
+	draw.DrawMask(dst, target, scaled, image.Point{}, mask, image.Point{}, draw.Over)
+
+	return dst, nil
+}
+
+func border(img image.Image, b Border) (image.Image, error) {
+	borderColor := color.RGBA{A: 255}
+	if b.Color != "" {
+		parsed, err := parseHexColor(b.Color)
+		if err != nil {
+			return nil, err
+		}
+		borderColor = parsed
+	}
+
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx()+2*b.Width, bounds.Dy()+2*b.Width))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(borderColor), image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(b.Width, b.Width, b.Width+bounds.Dx(), b.Width+bounds.Dy()), img, bounds.Min, draw.Src)
+	return dst, nil
+}
+
+func roundCorners(img image.Image, radius int) image.Image {
+	bounds := img.Bounds()
+	radius = min(radius, bounds.Dx()/2, bounds.Dy()/2)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.DrawMask(dst, dst.Bounds(), img, bounds.Min, &roundedMask{size: dst.Bounds(), radius: radius}, image.Point{}, draw.Src)
+	return dst
+}
+
+// roundedMask is an alpha mask for a rectangle with rounded corners.
+type roundedMask struct {
+	size   image.Rectangle
+	radius int
+}
+
+func (m *roundedMask) ColorModel() color.Model { return color.AlphaModel }
+
+func (m *roundedMask) Bounds() image.Rectangle { return m.size }
+
+func (m *roundedMask) At(x, y int) color.Color {
+	r := m.radius
+	w, h := m.size.Dx(), m.size.Dy()
+
+	cx, cy := -1, -1
+	switch {
+	case x < r && y < r:
+		cx, cy = r, r
+	case x >= w-r && y < r:
+		cx, cy = w-r-1, r
+	case x < r && y >= h-r:
+		cx, cy = r, h-r-1
+	case x >= w-r && y >= h-r:
+		cx, cy = w-r-1, h-r-1
+	}
+
+	if cx >= 0 {
+		dx, dy := x-cx, y-cy
+		if dx*dx+dy*dy > r*r {
+			return color.Alpha{}
+		}
+	}
+	return color.Alpha{A: 255}
+}
+
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+const (
+	sheetGap     = 24
+	sheetLabel   = 20
+	sheetMaxSide = 8192
+	// sheetMaxPixels caps the sheet at 16 megapixels, 64MB as RGBA.
+	sheetMaxPixels = 16 << 20
+)
+
+// SheetImage is one labelled tile of a contact sheet.
+type SheetImage struct {
+	Label string
+	Data  []byte
+}
+
+// ContactSheet lays the images out side by side, top aligned with a label
+// under each, on a white background. Tiles are scaled down together so the
+// sheet fits within 8192px on each side and 16 megapixels, and each image is
+// decoded and scaled in turn, so the full-size images are never held at once.
+func ContactSheet(images []SheetImage) ([]byte, error) {
+	sizes := make([]image.Point, 0, len(images))
+	totalW, maxH := 0, 0
+	for _, item := range images {
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(item.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		sizes = append(sizes, image.Pt(cfg.Width, cfg.Height))
+		totalW += cfg.Width
+		maxH = max(maxH, cfg.Height)
+	}
+
+	gapsW := sheetGap * (len(images) + 1)
+	gapsH := 2*sheetGap + sheetLabel
+	ratio := 1.0
+	if totalW > 0 && maxH > 0 {
+		ratio = min(ratio, float64(sheetMaxSide-gapsW)/float64(totalW), float64(sheetMaxSide-gapsH)/float64(maxH))
+		// Solve (totalW*r + gapsW) * (maxH*r + gapsH) <= sheetMaxPixels for r.
+		a := float64(totalW) * float64(maxH)
+		b := float64(totalW)*float64(gapsH) + float64(maxH)*float64(gapsW)
+		c := float64(gapsW)*float64(gapsH) - sheetMaxPixels
+		ratio = min(ratio, (-b+math.Sqrt(b*b-4*a*c))/(2*a))
+	}
+	if ratio <= 0 {
+		return nil, fmt.Errorf("too many images for a contact sheet")
+	}
+
+	width, height := gapsW, 0
+	for i, size := range sizes {
+		sizes[i] = image.Pt(max(1, int(float64(size.X)*ratio)), max(1, int(float64(size.Y)*ratio)))
+		width += sizes[i].X
+		height = max(height, sizes[i].Y)
+	}
+	height += gapsH
+
+	sheet := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(sheet, sheet.Bounds(), image.White, image.Point{}, draw.Src)
+
+	face := basicfont.Face7x13
+	x := sheetGap
+	for i, item := range images {
+		img, _, err := image.Decode(bytes.NewReader(item.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+
+		tile := image.Rect(x, sheetGap, x+sizes[i].X, sheetGap+sizes[i].Y)
+		if ratio < 1 {
+			draw.CatmullRom.Scale(sheet, tile, img, img.Bounds(), draw.Over, nil)
+		} else {
+			draw.Draw(sheet, tile, img, img.Bounds().Min, draw.Over)
+		}
+
+		drawer := &font.Drawer{
+			Dst:  sheet,
+			Src:  image.Black,
+			Face: face,
+			Dot:  fixed.P(x, height-sheetGap),
+		}
+		drawer.DrawString(item.Label)
+
+		x += sizes[i].X + sheetGap
+	}
+
+	return encodePNG(sheet)
+}
@@ -0,0 +1,163 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, width int, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// allocated returns the bytes allocated while running fn.
+func allocated(fn func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func TestTransformWatermarkAtLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		mark   Watermark
+	}{
+		{"max text and size", 800, 600, Watermark{Text: strings.Repeat("W", 200), Size: 1024}},
+		{"max size on a small image", 40, 20, Watermark{Text: "pagecap", Size: 1024, Position: PositionCenter}},
+		{"default size", 800, 600, Watermark{Text: strings.Repeat("W", 200)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testPNG(t, tt.width, tt.height)
+
+			var result *Result
+			var err error
+			used := allocated(func() {
+				result, err = Transform(data, Options{Watermark: &tt.mark})
+			})
+			if err != nil {
+				t.Fatalf("Transform: %v", err)
+			}
+
+			// The image itself is copied a few times; anything near the
+			// unclamped text size (hundreds of MB) is a regression.
+			if limit := uint64(64 << 20); used > limit {
+				t.Errorf("allocated %d bytes, want at most %d", used, limit)
+			}
+
+			img, err := png.Decode(bytes.NewReader(result.Image))
+			if err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			if got := img.Bounds().Size(); got != image.Pt(tt.width, tt.height) {
+				t.Errorf("result size = %v, want %dx%d", got, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestContactSheetFitsCaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		count  int
+		width  int
+		height int
+	}{
+		{"small tiles keep their size", 3, 320, 240},
+		{"tall full-page captures", 4, 1280, 6000},
+		{"many wide tiles", 10, 1920, 1080},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testPNG(t, tt.width, tt.height)
+			images := make([]SheetImage, tt.count)
+			for i := range images {
+				images[i] = SheetImage{Label: "viewport", Data: data}
+			}
+
+			out, err := ContactSheet(images)
+			if err != nil {
+				t.Fatalf("ContactSheet: %v", err)
+			}
+
+			cfg, err := png.DecodeConfig(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("decode sheet: %v", err)
+			}
+			if cfg.Width > sheetMaxSide || cfg.Height > sheetMaxSide || cfg.Width*cfg.Height > sheetMaxPixels {
+				t.Errorf("sheet is %dx%d, over the %dpx or %d pixel cap", cfg.Width, cfg.Height, sheetMaxSide, sheetMaxPixels)
+			}
+
+			natural := tt.count*(tt.width+sheetGap) + sheetGap
+			if natural*(tt.height+2*sheetGap+sheetLabel) <= sheetMaxPixels && cfg.Width != natural {
+				t.Errorf("sheet width = %d, want the natural %d", cfg.Width, natural)
+			}
+		})
+	}
+}
+
+func TestTransformResizeFitsCap(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		resize Resize
+	}{
+		{"tall page by width", 200, 20000, Resize{Width: 8192}},
+		{"wide page by height", 20000, 200, Resize{Height: 8192}},
+		{"fill at the limits", 100, 100, Resize{Width: 8192, Height: 8192, Mode: ResizeFill}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testPNG(t, tt.width, tt.height)
+
+			var result *Result
+			var err error
+			used := allocated(func() {
+				result, err = Transform(data, Options{Resize: &tt.resize})
+			})
+			if err != nil {
+				t.Fatalf("Transform: %v", err)
+			}
+			// A capped result and its encoding take a few hundred MB;
+			// uncapped, the tall page alone would need about 26GB.
+			if limit := uint64(1 << 30); used > limit {
+				t.Errorf("allocated %d bytes, want at most %d", used, limit)
+			}
+
+			cfg, err := png.DecodeConfig(bytes.NewReader(result.Image))
+			if err != nil {
+				t.Fatalf("decode result: %v", err)
+			}
+			if cfg.Width*cfg.Height > resizeMaxPixels {
+				t.Errorf("result is %dx%d, over the %d pixel cap", cfg.Width, cfg.Height, resizeMaxPixels)
+			}
+			// Capping keeps the shape the resize asked for.
+			got := float64(cfg.Width) / float64(cfg.Height)
+			want := float64(tt.width) / float64(tt.height)
+			if tt.resize.Mode == ResizeFill {
+				want = float64(tt.resize.Width) / float64(tt.resize.Height)
+			}
+			if math.Abs(got-want)/want > 0.05 {
+				t.Errorf("result aspect ratio = %.3f, want %.3f", got, want)
+			}
+		})
+	}
+}
@@ -24,21 +24,11 @@ type Service struct {
 var _ jwtContract.Service = (*Service)(nil)
 
 func NewJwtService(cfg *config.Config) jwtContract.Service {
-	accessDuration, err := time.ParseDuration(cfg.Jwt.AccessTokenExpire)
-	if err != nil {
-		panic(fmt.Errorf("failed to parse access token duration: %w", err))
-	}
-
-	refreshDuration, err := time.ParseDuration(cfg.Jwt.RefreshTokenExpire)
-	if err != nil {
-		panic(fmt.Errorf("failed to parse refresh token duration: %w", err))
-	}
-
 	return &Service{
 		accessSecret:  cfg.Jwt.AccessTokenSecret,
 		refreshSecret: cfg.Jwt.RefreshTokenSecret,
-		accessExpire:  accessDuration,
-		refreshExpire: refreshDuration,
+		accessExpire:  cfg.Jwt.AccessTokenExpire,
+		refreshExpire: cfg.Jwt.RefreshTokenExpire,
 	}
 }
 
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// apiKeyPrefixLength is how much of an API key is logged, enough to tell keys
+// apart without making the logged value usable.
+const apiKeyPrefixLength = 6
+
+// Setup configures the standard logrus logger. Every format goes through the
+// redacting formatter, so secrets are scrubbed from messages and fields.
+func Setup(format string, level string) error {
+	var formatter logrus.Formatter
+	switch format {
+	case FormatText, "":
+		formatter = &logrus.TextFormatter{FullTimestamp: true}
+	case FormatJSON:
+		formatter = &logrus.JSONFormatter{}
+	default:
+		return fmt.Errorf("format must be %s or %s", FormatText, FormatJSON)
+	}
+
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	logrus.SetFormatter(&redactingFormatter{inner: formatter})
+	logrus.SetLevel(parsed)
+	return nil
+}
+
+type ctxKey struct{}
+
+// requestFields is shared by everything handling one request, so fields added
+// late, like the user once authenticated, also reach the access log.
+type requestFields struct {
+	mu     sync.RWMutex
+	fields logrus.Fields
+}
+
+// NewContext returns a copy of ctx carrying a request logger with fields.
+func NewContext(ctx context.Context, fields logrus.Fields) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &requestFields{fields: maps.Clone(fields)})
+}
+
+// AddFields attaches fields to the request logger in ctx. It does nothing
+// when ctx has none.
+func AddFields(ctx context.Context, fields logrus.Fields) {
+	rf, ok := ctx.Value(ctxKey{}).(*requestFields)
+	if !ok {
+		return
+	}
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	maps.Copy(rf.fields, fields)
+}
+
+// FromContext returns a logger with the request's fields and trace ID, or the
+// standard logger's entry when ctx carries neither.
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	if ctx == nil {
+		return entry
+	}
+
+	if rf, ok := ctx.Value(ctxKey{}).(*requestFields); ok {
+		rf.mu.RLock()
+		entry = entry.WithFields(rf.fields)
+		rf.mu.RUnlock()
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		entry = entry.WithField("trace_id", sc.TraceID().String())
+	}
+	return entry
+}
+
+// APIKeyPrefix shortens key to the prefix that is safe to log.
+func APIKeyPrefix(key string) string {
+	if len(key) <= apiKeyPrefixLength {
+		return key
+	}
+	return key[:apiKeyPrefixLength]
+}
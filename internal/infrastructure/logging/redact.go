@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveFields are field names whose values are never logged.
+var sensitiveFields = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+	"secret":        true,
+	"api_key":       true,
+	"signature":     true,
+}
+
+var (
+	bearerPattern      = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+	jwtPattern         = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	keyValuePattern    = regexp.MustCompile(`(?i)\b((?:password|secret|token|access_token|refresh_token|api_key|apikey|signature)["']?\s*[:=]\s*["']?)[^\s"'&,]+`)
+	capturePathPattern = regexp.MustCompile(`(/page-capture/)([^/?\s]+)`)
+)
+
+// Redact scrubs bearer tokens, JWTs, secret key=value pairs and API keys in
+// page capture paths from s. Capture IDs in the same paths are kept.
+func Redact(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "${1}"+redacted)
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = keyValuePattern.ReplaceAllString(s, "${1}"+redacted)
+	s = capturePathPattern.ReplaceAllStringFunc(s, func(match string) string {
+		segment := strings.TrimPrefix(match, "/page-capture/")
+		if _, err := uuid.Parse(segment); err == nil {
+			return match
+		}
+		return "/page-capture/" + APIKeyPrefix(segment) + "..."
+	})
+	return s
+}
+
+// redactingFormatter scrubs the message and fields before handing the entry
+// to the configured formatter.
+type redactingFormatter struct {
+	inner logrus.Formatter
+}
+
+func (f *redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	clean := *entry
+	clean.Message = Redact(entry.Message)
+	clean.Data = make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		switch {
+		case sensitiveFields[strings.ToLower(key)]:
+			clean.Data[key] = redacted
+		case key == logrus.ErrorKey:
+			if err, ok := value.(error); ok {
+				clean.Data[key] = Redact(err.Error())
+			} else {
+				clean.Data[key] = value
+			}
+		default:
+			if s, ok := value.(string); ok {
+				clean.Data[key] = Redact(s)
+			} else {
+				clean.Data[key] = value
+			}
+		}
+	}
+	return f.inner.Format(&clean)
+}
@@ -3,7 +3,6 @@ package mail
 import (
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
 	"gopkg.in/gomail.v2"
-	"strconv"
 )
 
 type Service struct {
@@ -11,14 +10,9 @@ type Service struct {
 }
 
 func NewMailService(config *config.Config) *Service {
-	port, err := strconv.Atoi(config.Mail.Port)
-	if err != nil {
-		panic("Invalid mail port")
-	}
-
 	mail := gomail.Dialer{
 		Host:     config.Mail.Host,
-		Port:     port,
+		Port:     config.Mail.Port,
 		Username: config.Mail.Username,
 		Password: config.Mail.Password,
 	}
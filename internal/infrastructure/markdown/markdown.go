@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// renderer converts GitHub flavoured Markdown. Raw HTML in the source is
+// omitted, as goldmark does by default.
+var renderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+const documentTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.6; color: #1f2328; max-width: 860px; margin: 0 auto; padding: 32px; }
+pre, code { font-family: ui-monospace, Menlo, Consolas, monospace; background: #f6f8fa; border-radius: 6px; }
+pre { padding: 16px; overflow: auto; }
+code { padding: 0.2em 0.4em; }
+pre code { padding: 0; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 6px 13px; }
+blockquote { margin: 0; padding: 0 1em; color: #59636e; border-left: 0.25em solid #d0d7de; }
+img { max-width: 100%%; }
+</style>
+</head>
+<body>
+%s
+</body>
+</html>`
+
+// ToHTML renders Markdown into a standalone, lightly styled HTML document.
+func ToHTML(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+	return fmt.Sprintf(documentTemplate, buf.String()), nil
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "pagecap"
+
+// Capture phases observed by CapturePhaseDuration.
+const (
+	PhaseNavigate = "navigate"
+	PhaseWait     = "wait"
+	PhaseRender   = "render"
+	PhaseUpload   = "upload"
+)
+
+// Dependencies counted by DependencyErrors.
+const (
+	DependencyPostgres = "postgres"
+	DependencyRedis    = "redis"
+)
+
+var captureBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120}
+
+// HTTPRequestDuration is labelled by the matched route pattern rather than
+// the raw path, so API keys and IDs in URLs do not create new series.
+var HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "http_request_duration_seconds",
+	Help:      "HTTP request latency by method, route and status.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+var CapturePhaseDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "capture_phase_duration_seconds",
+	Help:      "Time spent in each capture phase: navigate, wait, render and upload.",
+	Buckets:   captureBuckets,
+}, []string{"phase"})
+
+// CaptureCancellations counts captures abandoned before completion, by
+// reason: "timeout" when the capture deadline passed and "client_gone" when
+// the caller disconnected.
+var CaptureCancellations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "capture_cancellations_total",
+	Help:      "Captures abandoned before completion, by reason.",
+}, []string{"reason"})
+
+var BrowserOpenPages = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "browser_open_pages",
+	Help:      "Browser tabs currently open.",
+})
+
+// BrowserCapturesInFlight counts captures and recordings holding or waiting
+// for a tab. Captures above the tab limit wait for a free tab, so anything
+// beyond it is queued work.
+var BrowserCapturesInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "browser_captures_in_flight",
+	Help:      "Captures and recordings currently running in the browser.",
+})
+
+// BrowserContextDuration measures the per-capture cost of incognito
+// isolation: creating a browser context ("create") and disposing of it
+// ("dispose").
+var BrowserContextDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "browser_context_duration_seconds",
+	Help:      "Time spent creating and disposing of isolated browser contexts.",
+	Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+}, []string{"operation"})
+
+var BrowserRestarts = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "browser_restarts_total",
+	Help:      "Browser restarts, including idle restarts.",
+})
+
+var UploadQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "upload_queue_depth",
+	Help:      "Upload jobs that are due and waiting for the worker.",
+})
+
+// Uploads counts upload attempts by result: "stored", "retry" or
+// "dead_letter".
+var Uploads = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "uploads_total",
+	Help:      "Upload attempts by result.",
+}, []string{"result"})
+
+var DependencyErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "dependency_errors_total",
+	Help:      "Failed calls to Postgres and Redis.",
+}, []string{"dependency"})
+
+var RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "rate_limit_rejections_total",
+	Help:      "Requests rejected by a rate limit, by limit.",
+}, []string{"limit"})
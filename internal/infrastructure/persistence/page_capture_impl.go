@@ -1,11 +1,22 @@
 package persistence
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
 	baseRepository "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/repository"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PageCaptureImpl struct {
@@ -29,71 +40,305 @@ func (p *PageCaptureImpl) GetUser(userID string) (*entity.User, error) {
 	return &user, nil
 }
 
-func (p *PageCaptureImpl) GetPageCaptureByUserID(
-	userID string,
-	search string,
-	orderBy string,
-	sort string,
-	page int,
-	fullPage *bool,
-	isMobile *bool,
-) (*dto.PagesCaptureResponse, error) {
+// pageCaptureSortColumns maps whitelisted sort fields onto SQL expressions.
+// Nullable columns are coalesced so keyset comparisons stay well defined.
+var pageCaptureSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"url":        "url",
+	"domain":     "domain",
+	"width":      "COALESCE(width, 0)",
+	"height":     "COALESCE(height, 0)",
+}
 
-	var captures []entity.PageCapture
+type pageCaptureCursor struct {
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func (p *PageCaptureImpl) GetPageCaptureByUserID(ctx context.Context, userID string, q *dto.PageCaptureQuery) (*dto.PagesCaptureResponse, error) {
+	column, ok := pageCaptureSortColumns[q.OrderBy]
+	if !ok {
+		return nil, errorEntity.ErrInvalidRequest
+	}
+
+	captures := make([]entity.PageCapture, 0, q.Limit)
 	var total int64
 
-	limit := 10
-	offset := (page - 1) * limit
+	query := p.DB.WithContext(ctx).Model(&entity.PageCapture{}).Where("user_id = ?", userID)
 
-	if sort != "asc" && sort != "desc" {
-		sort = "desc"
+	if q.Search != "" {
+		search := "%" + q.Search + "%"
+		query = query.Where(`(
+			url ILIKE ? OR 
+			image_path ILIKE ? OR 
+			public_id ILIKE ? OR 
+			title ILIKE ? OR 
+			description ILIKE ? OR 
+			canonical_url ILIKE ? OR 
+			final_url ILIKE ? OR 
+			open_graph::text ILIKE ? OR 
+			twitter_card::text ILIKE ?
+		)`, search, search, search, search, search, search, search, search, search)
 	}
 
-	if orderBy == "" {
-		orderBy = "created_at"
+	if q.From != nil {
+		query = query.Where("created_at >= ?", *q.From)
 	}
 
-	query := p.DB.Model(&entity.PageCapture{}).Where("user_id = ?", userID)
+	if q.To != nil {
+		query = query.Where("created_at < ?", *q.To)
+	}
 
-	if search != "" {
-		query = query.Where(`
-			url ILIKE ? OR 
-			image_path ILIKE ? OR 
-			public_id ILIKE ?
-		`, "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	if q.Domain != "" {
+		query = query.Where(`(domain = ? OR domain LIKE ? ESCAPE '\')`, q.Domain, "%."+escapeLike(q.Domain))
 	}
 
-	if fullPage != nil {
-		query = query.Where("full_page = ?", *fullPage)
+	if q.Format != "" {
+		query = query.Where("format = ?", q.Format)
 	}
 
-	if isMobile != nil {
-		query = query.Where("is_mobile = ?", *isMobile)
+	if q.Width != nil {
+		query = query.Where("width = ?", *q.Width)
 	}
 
-	if err := query.Count(&total).Error; err != nil {
+	if q.Height != nil {
+		query = query.Where("height = ?", *q.Height)
+	}
+
+	if q.FullPage != nil {
+		query = query.Where("full_page = ?", *q.FullPage)
+	}
+
+	if q.IsMobile != nil {
+		query = query.Where("is_mobile = ?", *q.IsMobile)
+	}
+
+	if q.StatusCode != nil {
+		query = query.Where("status_code = ?", *q.StatusCode)
+	}
+
+	if q.BatchID != nil {
+		query = query.Where("batch_id = ?", *q.BatchID)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
+	if q.Cursor != "" {
+		cursor, value, err := decodePageCaptureCursor(q.Cursor, q.OrderBy)
+		if err != nil {
+			return nil, errorEntity.ErrInvalidRequest
+		}
+
+		op := "<"
+		if q.Sort == "asc" {
+			op = ">"
+		}
+		query = query.Where(fmt.Sprintf("(%s, uuid) %s (?, ?)", column, op), value, cursor.ID)
+	} else {
+		query = query.Offset((q.Page - 1) * q.Limit)
+	}
+
 	err := query.
-		Order(orderBy + " " + sort).
-		Limit(limit).
-		Offset(offset).
+		Preload("Artifacts").
+		Order(fmt.Sprintf("%s %s, uuid %s", column, q.Sort, q.Sort)).
+		Limit(q.Limit + 1).
 		Find(&captures).Error
 
 	if err != nil {
 		return nil, err
 	}
 
-	totalPages := int((total + int64(limit) - 1) / int64(limit))
-
 	result := &dto.PagesCaptureResponse{
-		Data:       captures,
 		Total:      total,
-		Page:       page,
-		Limit:      limit,
-		TotalPages: totalPages,
+		Page:       q.Page,
+		Limit:      q.Limit,
+		TotalPages: int((total + int64(q.Limit) - 1) / int64(q.Limit)),
+	}
+
+	if len(captures) > q.Limit {
+		captures = captures[:q.Limit]
+		next, err := encodePageCaptureCursor(captures[len(captures)-1], q.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = next
 	}
+	result.Data = captures
 
 	return result, nil
 }
+
+func encodePageCaptureCursor(last entity.PageCapture, orderBy string) (string, error) {
+	var value string
+	switch orderBy {
+	case "created_at":
+		value = last.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		value = last.UpdatedAt.Format(time.RFC3339Nano)
+	case "url":
+		value = last.URL
+	case "domain":
+		value = last.Domain
+	case "width":
+		value = strconv.Itoa(intOrZero(last.Width))
+	case "height":
+		value = strconv.Itoa(intOrZero(last.Height))
+	}
+
+	raw, err := json.Marshal(pageCaptureCursor{Value: value, ID: last.UUID})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodePageCaptureCursor(encoded string, orderBy string) (*pageCaptureCursor, any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cursor pageCaptureCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, nil, err
+	}
+
+	switch orderBy {
+	case "created_at", "updated_at":
+		value, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		return &cursor, value, err
+	case "width", "height":
+		value, err := strconv.Atoi(cursor.Value)
+		return &cursor, value, err
+	default:
+		return &cursor, cursor.Value, nil
+	}
+}
+
+func intOrZero(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+// CreatePending stores the capture row and its artifacts together with the
+// outbox jobs that will upload their bytes, so a capture is never recorded
+// without a way to store it.
+func (p *PageCaptureImpl) CreatePending(ctx context.Context, capture *entity.PageCapture, jobs []*entity.UploadJob) error {
+	return p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Artifacts").Create(capture).Error; err != nil {
+			return err
+		}
+
+		for i := range capture.Artifacts {
+			capture.Artifacts[i].CaptureID = capture.UUID
+			if err := tx.Create(&capture.Artifacts[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, job := range jobs {
+			job.CaptureID = capture.UUID
+			if err := tx.Create(job).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (p *PageCaptureImpl) GetUsersWithRetention(ctx context.Context) ([]entity.User, error) {
+	var users []entity.User
+	err := p.DB.WithContext(ctx).
+		Where("retention_days IS NOT NULL OR retention_max_captures IS NOT NULL").
+		Find(&users).Error
+	return users, err
+}
+
+func (p *PageCaptureImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.PageCapture, error) {
+	var captures []entity.PageCapture
+	err := p.DB.WithContext(ctx).Where("user_id = ?", userID).Find(&captures).Error
+	return captures, err
+}
+
+func (p *PageCaptureImpl) FindByUserIDAndUUIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]entity.PageCapture, error) {
+	var captures []entity.PageCapture
+	err := p.DB.WithContext(ctx).Where("user_id = ? AND uuid IN ?", userID, ids).Find(&captures).Error
+	return captures, err
+}
+
+func (p *PageCaptureImpl) FindCreatedBefore(ctx context.Context, userID uuid.UUID, before time.Time, limit int) ([]entity.PageCapture, error) {
+	var captures []entity.PageCapture
+	err := p.DB.WithContext(ctx).
+		Where("user_id = ? AND created_at < ?", userID, before).
+		Order("created_at asc").
+		Limit(limit).
+		Find(&captures).Error
+	return captures, err
+}
+
+func (p *PageCaptureImpl) FindBeyondLatest(ctx context.Context, userID uuid.UUID, keep int, limit int) ([]entity.PageCapture, error) {
+	var captures []entity.PageCapture
+	err := p.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at desc").
+		Offset(keep).
+		Limit(limit).
+		Find(&captures).Error
+	return captures, err
+}
+
+// DeleteByUUIDs returns the deleted rows so that objects an upload stored
+// after the caller read them are not left behind.
+func (p *PageCaptureImpl) DeleteByUUIDs(ctx context.Context, ids []uuid.UUID) ([]entity.PageCapture, []entity.CaptureArtifact, error) {
+	var captures []entity.PageCapture
+	var artifacts []entity.CaptureArtifact
+	if len(ids) == 0 {
+		return captures, artifacts, nil
+	}
+
+	err := p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("capture_id IN ?", ids).Delete(&entity.UploadJob{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Returning{}).Where("capture_id IN ?", ids).Delete(&artifacts).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.Returning{}).Where("uuid IN ?", ids).Delete(&captures).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return captures, artifacts, nil
+}
+
+func (p *PageCaptureImpl) FindArtifactsByCaptureIDs(ctx context.Context, captureIDs []uuid.UUID) ([]entity.CaptureArtifact, error) {
+	var artifacts []entity.CaptureArtifact
+	if len(captureIDs) == 0 {
+		return artifacts, nil
+	}
+	err := p.DB.WithContext(ctx).Where("capture_id IN ?", captureIDs).Order("created_at asc").Find(&artifacts).Error
+	return artifacts, err
+}
+
+func (p *PageCaptureImpl) FindArtifact(ctx context.Context, captureID uuid.UUID, artifactID uuid.UUID) (*entity.CaptureArtifact, error) {
+	var artifact entity.CaptureArtifact
+	err := p.DB.WithContext(ctx).Where("capture_id = ? AND uuid = ?", captureID, artifactID).Take(&artifact).Error
+	if err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+// escapeLike escapes LIKE wildcards so value only matches literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,33 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	baseRepository "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TemplateImpl struct {
+	*baseRepository.Repository[entity.Template]
+}
+
+var _ repository.TemplateRepository = (*TemplateImpl)(nil)
+
+func NewTemplateRepository(db *gorm.DB) *TemplateImpl {
+	return &TemplateImpl{
+		Repository: &baseRepository.Repository[entity.Template]{DB: db},
+	}
+}
+
+func (t *TemplateImpl) FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Template, error) {
+	templates := []entity.Template{}
+	err := t.DB.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(&templates).Error
+	return templates, err
+}
+
+func (t *TemplateImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return t.DB.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.Template{}).Error
+}
@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	baseRepository "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UploadJobImpl struct {
+	*baseRepository.Repository[entity.UploadJob]
+}
+
+var _ repository.UploadJobRepository = (*UploadJobImpl)(nil)
+
+func NewUploadJobRepository(db *gorm.DB) *UploadJobImpl {
+	return &UploadJobImpl{
+		Repository: &baseRepository.Repository[entity.UploadJob]{DB: db},
+	}
+}
+
+func (u *UploadJobImpl) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]entity.UploadJob, error) {
+	var jobs []entity.UploadJob
+	now := time.Now()
+
+	err := u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entity.UploadJobStatusPending, now).
+			Order("next_attempt_at asc").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(jobs))
+		for _, job := range jobs {
+			ids = append(ids, job.UUID)
+		}
+
+		return tx.Model(&entity.UploadJob{}).
+			Where("uuid IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (u *UploadJobImpl) Complete(ctx context.Context, job *entity.UploadJob, key string, url string) error {
+	// The job is removed either way, so a missing row is only reported once
+	// the transaction has committed.
+	var missing bool
+	err := u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		if job.ArtifactID != nil {
+			result = tx.Model(&entity.CaptureArtifact{}).
+				Where("uuid = ?", *job.ArtifactID).
+				Updates(map[string]any{
+					"public_id":  key,
+					"status":     entity.PageCaptureStatusStored,
+					"updated_at": time.Now(),
+				})
+		} else {
+			result = tx.Model(&entity.PageCapture{}).
+				Where("uuid = ?", job.CaptureID).
+				Updates(map[string]any{
+					"public_id":    key,
+					"image_path":   url,
+					"content_type": job.ContentType,
+					"status":       entity.PageCaptureStatusStored,
+					"updated_at":   time.Now(),
+				})
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		missing = result.RowsAffected == 0
+		return tx.Delete(job).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if missing {
+		return errorEntity.ErrDataNotFound
+	}
+	return nil
+}
+
+func (u *UploadJobImpl) Retry(ctx context.Context, job *entity.UploadJob, cause error, next time.Time) error {
+	return u.DB.WithContext(ctx).Model(job).Updates(map[string]any{
+		"attempts":        job.Attempts + 1,
+		"next_attempt_at": next,
+		"last_error":      cause.Error(),
+		"updated_at":      time.Now(),
+	}).Error
+}
+
+func (u *UploadJobImpl) Release(ctx context.Context, job *entity.UploadJob) error {
+	return u.DB.WithContext(ctx).Model(job).Updates(map[string]any{
+		"next_attempt_at": time.Now(),
+		"updated_at":      time.Now(),
+	}).Error
+}
+
+func (u *UploadJobImpl) DeadLetter(ctx context.Context, job *entity.UploadJob, cause error) error {
+	return u.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(job).Updates(map[string]any{
+			"attempts":   job.Attempts + 1,
+			"status":     entity.UploadJobStatusDead,
+			"last_error": cause.Error(),
+			"updated_at": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if job.ArtifactID != nil {
+			return tx.Model(&entity.CaptureArtifact{}).
+				Where("uuid = ?", *job.ArtifactID).
+				Updates(map[string]any{
+					"status":     entity.PageCaptureStatusFailed,
+					"updated_at": time.Now(),
+				}).Error
+		}
+
+		return tx.Model(&entity.PageCapture{}).
+			Where("uuid = ?", job.CaptureID).
+			Updates(map[string]any{
+				"status":     entity.PageCaptureStatusFailed,
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+func (u *UploadJobImpl) CountDue(ctx context.Context) (int64, error) {
+	var total int64
+	err := u.DB.WithContext(ctx).Model(&entity.UploadJob{}).
+		Where("status = ? AND next_attempt_at <= ?", entity.UploadJobStatusPending, time.Now()).
+		Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// errorMetricsHook counts failed Redis commands. A missing key (redis.Nil)
+// is a normal result and is not counted.
+type errorMetricsHook struct{}
+
+func (errorMetricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		countError(err)
+		return conn, err
+	}
+}
+
+func (errorMetricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		countError(err)
+		return err
+	}
+}
+
+func (errorMetricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		countError(err)
+		return err
+	}
+}
+
+func countError(err error) {
+	if err != nil && !errors.Is(err, redis.Nil) {
+		metrics.DependencyErrors.WithLabelValues(metrics.DependencyRedis).Inc()
+	}
+}
@@ -12,14 +12,15 @@ func NewRedis(cfg *config.Config) (*redis.Client, error) {
 	host := cfg.Redis.Host
 	port := cfg.Redis.Port
 	password := cfg.Redis.Password
-	if host == "" || port == "" {
+	if host == "" || port == 0 {
 		return nil, fmt.Errorf("redis host or port is not set in the configuration")
 	}
 
 	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", host, port),
+		Addr:     fmt.Sprintf("%s:%d", host, port),
 		Password: password,
 	})
+	rdb.AddHook(errorMetricsHook{})
 
 	logrus.Info("Connecting to Redis...")
 
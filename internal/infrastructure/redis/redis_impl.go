@@ -70,3 +70,7 @@ func (r *Service) Decr(key string) (int64, error) {
 func (r *Service) Expire(key string, expiration time.Duration) error {
 	return r.redisClient.Expire(context.Background(), key, expiration).Err()
 }
+
+func (r *Service) Ping(ctx context.Context) error {
+	return r.redisClient.Ping(ctx).Err()
+}
@@ -0,0 +1,150 @@
+package rod
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	ActionClick   = "click"
+	ActionType    = "type"
+	ActionPress   = "press"
+	ActionScroll  = "scroll"
+	ActionHover   = "hover"
+	ActionSelect  = "select"
+	ActionWaitFor = "waitFor"
+	ActionWait    = "wait"
+)
+
+// DefaultActionTimeout bounds a step that does not set its own timeout.
+const DefaultActionTimeout = 10 * time.Second
+
+// Action is a single browser step run before the capture. Selector targets
+// the element for every type except press and wait; Text is typed, Key is a
+// key name such as "Enter" or a single character, Value is the visible text
+// of the option to select and Duration is how long wait sleeps.
+type Action struct {
+	Type     string
+	Selector string
+	Text     string
+	Key      string
+	Value    string
+	Duration time.Duration
+	Timeout  time.Duration
+}
+
+// ActionError reports which step failed, counting from zero.
+type ActionError struct {
+	Index int
+	Type  string
+	Err   error
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("action %d (%s) failed: %v", e.Index, e.Type, e.Err)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+var namedKeys = map[string]input.Key{
+	"Enter":      input.Enter,
+	"Tab":        input.Tab,
+	"Escape":     input.Escape,
+	"Backspace":  input.Backspace,
+	"Delete":     input.Delete,
+	"Space":      input.Space,
+	"ArrowUp":    input.ArrowUp,
+	"ArrowDown":  input.ArrowDown,
+	"ArrowLeft":  input.ArrowLeft,
+	"ArrowRight": input.ArrowRight,
+	"Home":       input.Home,
+	"End":        input.End,
+	"PageUp":     input.PageUp,
+	"PageDown":   input.PageDown,
+}
+
+// ParseKey resolves a key name or a single printable character.
+func ParseKey(name string) (input.Key, error) {
+	if key, ok := namedKeys[name]; ok {
+		return key, nil
+	}
+
+	runes := []rune(name)
+	if len(runes) == 1 {
+		return input.Key(runes[0]), nil
+	}
+
+	return 0, fmt.Errorf("unsupported key %q", name)
+}
+
+// runActions executes the steps in order, each with its own timeout, and
+// stops at the first failure.
+func runActions(page *rod.Page, actions []Action) error {
+	for i, action := range actions {
+		logging.FromContext(page.GetContext()).Infof("Running action %d (%s)", i, action.Type)
+		if err := runAction(page, action); err != nil {
+			return &ActionError{Index: i, Type: action.Type, Err: err}
+		}
+	}
+	return nil
+}
+
+func runAction(page *rod.Page, action Action) error {
+	timeout := action.Timeout
+	if timeout <= 0 {
+		timeout = DefaultActionTimeout
+	}
+	if action.Type == ActionWait {
+		timeout = max(timeout, action.Duration+time.Second)
+	}
+
+	ctx, cancel := context.WithTimeout(page.GetContext(), timeout)
+	defer cancel()
+	page = page.Context(ctx)
+
+	switch action.Type {
+	case ActionWait:
+		select {
+		case <-time.After(action.Duration):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case ActionPress:
+		key, err := ParseKey(action.Key)
+		if err != nil {
+			return err
+		}
+		return page.Keyboard.Type(key)
+	}
+
+	el, err := page.Element(action.Selector)
+	if err != nil {
+		return fmt.Errorf("element %q not found: %w", action.Selector, err)
+	}
+
+	switch action.Type {
+	case ActionClick:
+		return el.Click(proto.InputMouseButtonLeft, 1)
+	case ActionType:
+		return el.Input(action.Text)
+	case ActionScroll:
+		return el.ScrollIntoView()
+	case ActionHover:
+		return el.Hover()
+	case ActionSelect:
+		return el.Select([]string{action.Value}, true, rod.SelectorTypeText)
+	case ActionWaitFor:
+		return el.WaitVisible()
+	default:
+		return fmt.Errorf("unknown action type %q", action.Type)
+	}
+}
@@ -0,0 +1,514 @@
+package rod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+)
+
+const (
+	ArtifactConsole = "console"
+	ArtifactHAR     = "har"
+	ArtifactTrace   = "trace"
+)
+
+const traceStopTimeout = 30 * time.Second
+
+// traceCategories mirrors the categories the DevTools performance panel records.
+var traceCategories = []string{
+	"devtools.timeline",
+	"v8.execute",
+	"disabled-by-default-devtools.timeline",
+	"disabled-by-default-devtools.timeline.frame",
+	"disabled-by-default-devtools.timeline.stack",
+	"disabled-by-default-v8.cpu_profiler",
+	"toplevel",
+	"blink.console",
+	"blink.user_timing",
+	"latencyInfo",
+}
+
+type ConsoleMessage struct {
+	Type      string    `json:"type"`
+	Text      string    `json:"text"`
+	URL       string    `json:"url,omitempty"`
+	Line      int       `json:"line,omitempty"`
+	Column    int       `json:"column,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type PageException struct {
+	Message   string    `json:"message"`
+	URL       string    `json:"url,omitempty"`
+	Line      int       `json:"line"`
+	Column    int       `json:"column"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type consoleLog struct {
+	Messages   []ConsoleMessage `json:"messages"`
+	Exceptions []PageException  `json:"exceptions"`
+}
+
+type networkEntry struct {
+	request    *proto.NetworkRequest
+	response   *proto.NetworkResponse
+	started    time.Time
+	startedAt  proto.MonotonicTime
+	finishedAt proto.MonotonicTime
+	size       float64
+	failure    string
+}
+
+// debugRecorder collects console output, uncaught exceptions and network
+// activity of a page through CDP events until it is finished.
+type debugRecorder struct {
+	mu         sync.Mutex
+	console    []ConsoleMessage
+	exceptions []PageException
+	requests   map[proto.NetworkRequestID]*networkEntry
+	entries    []*networkEntry
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func startDebugRecorder(page *rod.Page) *debugRecorder {
+	ctx, cancel := context.WithCancel(page.GetContext())
+	r := &debugRecorder{
+		requests: map[proto.NetworkRequestID]*networkEntry{},
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	wait := page.Context(ctx).EachEvent(
+		r.onConsole,
+		r.onException,
+		r.onRequest,
+		r.onResponse,
+		r.onFinished,
+		r.onFailed,
+	)
+
+	go func() {
+		defer close(r.done)
+		wait()
+	}()
+
+	return r
+}
+
+// stop detaches the event listeners. It is safe to call more than once.
+func (r *debugRecorder) stop() {
+	r.cancel()
+	<-r.done
+}
+
+// finish stops recording and returns the console log and HAR artifacts.
+func (r *debugRecorder) finish() ([]Artifact, error) {
+	r.stop()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	console, err := json.MarshalIndent(consoleLog{
+		Messages:   nonNil(r.console),
+		Exceptions: nonNil(r.exceptions),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode console log: %w", err)
+	}
+
+	har, err := json.MarshalIndent(buildHAR(r.entries), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode har: %w", err)
+	}
+
+	return []Artifact{
+		{
+			Kind:        ArtifactConsole,
+			Filename:    "console.json",
+			ContentType: "application/json",
+			Data:        console,
+		},
+		{
+			Kind:        ArtifactHAR,
+			Filename:    "network.har",
+			ContentType: "application/json",
+			Data:        har,
+		},
+	}, nil
+}
+
+func (r *debugRecorder) onConsole(e *proto.RuntimeConsoleAPICalled) {
+	msg := ConsoleMessage{
+		Type:      string(e.Type),
+		Text:      remoteObjectsText(e.Args),
+		Timestamp: time.UnixMilli(int64(e.Timestamp)),
+	}
+	if e.StackTrace != nil && len(e.StackTrace.CallFrames) > 0 {
+		frame := e.StackTrace.CallFrames[0]
+		msg.URL = frame.URL
+		msg.Line = frame.LineNumber + 1
+		msg.Column = frame.ColumnNumber + 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.console = append(r.console, msg)
+}
+
+func (r *debugRecorder) onException(e *proto.RuntimeExceptionThrown) {
+	details := e.ExceptionDetails
+	if details == nil {
+		return
+	}
+
+	message := details.Text
+	if details.Exception != nil && details.Exception.Description != "" {
+		message = details.Exception.Description
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exceptions = append(r.exceptions, PageException{
+		Message:   message,
+		URL:       details.URL,
+		Line:      details.LineNumber + 1,
+		Column:    details.ColumnNumber + 1,
+		Timestamp: time.UnixMilli(int64(e.Timestamp)),
+	})
+}
+
+func (r *debugRecorder) onRequest(e *proto.NetworkRequestWillBeSent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A redirect reuses the request ID: close the previous hop with the
+	// redirect response before recording the new one.
+	if prev, ok := r.requests[e.RequestID]; ok && e.RedirectResponse != nil {
+		prev.response = e.RedirectResponse
+		prev.finishedAt = e.Timestamp
+	}
+
+	entry := &networkEntry{
+		request:   e.Request,
+		started:   e.WallTime.Time(),
+		startedAt: e.Timestamp,
+	}
+	r.requests[e.RequestID] = entry
+	r.entries = append(r.entries, entry)
+}
+
+func (r *debugRecorder) onResponse(e *proto.NetworkResponseReceived) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.requests[e.RequestID]; ok {
+		entry.response = e.Response
+	}
+}
+
+func (r *debugRecorder) onFinished(e *proto.NetworkLoadingFinished) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.requests[e.RequestID]; ok {
+		entry.finishedAt = e.Timestamp
+		entry.size = e.EncodedDataLength
+	}
+}
+
+func (r *debugRecorder) onFailed(e *proto.NetworkLoadingFailed) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry, ok := r.requests[e.RequestID]; ok {
+		entry.finishedAt = e.Timestamp
+		entry.failure = e.ErrorText
+	}
+}
+
+func remoteObjectsText(args []*proto.RuntimeRemoteObject) string {
+	text := ""
+	for i, arg := range args {
+		if i > 0 {
+			text += " "
+		}
+		switch {
+		case arg.Type == proto.RuntimeRemoteObjectTypeString:
+			text += arg.Value.Str()
+		case arg.UnserializableValue != "":
+			text += string(arg.UnserializableValue)
+		case arg.Description != "":
+			text += arg.Description
+		default:
+			text += arg.Value.JSON("", "")
+		}
+	}
+	return text
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// startTrace begins recording a Chrome performance trace on the page.
+func startTrace(page *rod.Page) error {
+	err := proto.TracingStart{
+		TransferMode: proto.TracingStartTransferModeReportEvents,
+		TraceConfig: &proto.TracingTraceConfig{
+			IncludedCategories: traceCategories,
+			ExcludedCategories: []string{"*"},
+		},
+	}.Call(page)
+	if err != nil {
+		return fmt.Errorf("failed to start trace: %w", err)
+	}
+	return nil
+}
+
+// stopTrace ends the trace and returns it in the Chrome trace event format,
+// which DevTools and Perfetto can open.
+func stopTrace(page *rod.Page) (*Artifact, error) {
+	ctx, cancel := context.WithTimeout(page.GetContext(), traceStopTimeout)
+	defer cancel()
+
+	var events []map[string]gson.JSON
+	wait := page.Context(ctx).EachEvent(
+		func(e *proto.TracingDataCollected) {
+			events = append(events, e.Value...)
+		},
+		func(e *proto.TracingTracingComplete) bool {
+			return true
+		},
+	)
+
+	if err := (proto.TracingEnd{}).Call(page); err != nil {
+		return nil, fmt.Errorf("failed to stop trace: %w", err)
+	}
+	wait()
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("failed to collect trace: %w", ctx.Err())
+	}
+
+	data, err := json.Marshal(map[string]any{"traceEvents": nonNil(events)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode trace: %w", err)
+	}
+
+	return &Artifact{
+		Kind:        ArtifactTrace,
+		Filename:    "trace.json",
+		ContentType: "application/json",
+		Data:        data,
+	}, nil
+}
+
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []struct{}  `json:"cookies"`
+	Headers     []harHeader `json:"headers"`
+	QueryString []harHeader `json:"queryString"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []struct{}  `json:"cookies"`
+	Headers     []harHeader `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func buildHAR(entries []*networkEntry) har {
+	out := har{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "PageCapAPI", Version: "1.0"},
+			Entries: make([]harEntry, 0, len(entries)),
+		},
+	}
+
+	for _, entry := range entries {
+		if entry.request == nil {
+			continue
+		}
+		out.Log.Entries = append(out.Log.Entries, entry.har())
+	}
+
+	return out
+}
+
+func (n *networkEntry) har() harEntry {
+	entry := harEntry{
+		StartedDateTime: n.started,
+		Time:            -1,
+		Request: harRequest{
+			Method:      n.request.Method,
+			URL:         n.request.URL,
+			HTTPVersion: "",
+			Cookies:     []struct{}{},
+			Headers:     harHeaders(n.request.Headers),
+			QueryString: harQuery(n.request.URL),
+			HeadersSize: -1,
+			BodySize:    len(n.request.PostData),
+		},
+		Response: harResponse{
+			Cookies:     []struct{}{},
+			Headers:     []harHeader{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Send: 0, Wait: 0, Receive: 0},
+		Error:   n.failure,
+	}
+
+	if n.finishedAt > 0 {
+		entry.Time = millis(n.finishedAt - n.startedAt)
+	}
+
+	res := n.response
+	if res == nil {
+		return entry
+	}
+
+	entry.Request.HTTPVersion = res.Protocol
+	entry.Response.Status = res.Status
+	entry.Response.StatusText = res.StatusText
+	entry.Response.HTTPVersion = res.Protocol
+	entry.Response.Headers = harHeaders(res.Headers)
+	entry.Response.Content = harContent{Size: int(n.size), MimeType: res.MIMEType}
+	entry.Response.BodySize = int(n.size)
+	entry.ServerIPAddress = res.RemoteIPAddress
+	if location, ok := res.Headers["Location"]; ok {
+		entry.Response.RedirectURL = location.Str()
+	} else if location, ok := res.Headers["location"]; ok {
+		entry.Response.RedirectURL = location.Str()
+	}
+
+	if t := res.Timing; t != nil {
+		entry.Timings = harTimings{
+			Blocked: firstNonNegative(t.DNSStart, t.ConnectStart, t.SendStart),
+			DNS:     span(t.DNSStart, t.DNSEnd),
+			Connect: span(t.ConnectStart, t.ConnectEnd),
+			SSL:     span(t.SslStart, t.SslEnd),
+			Send:    t.SendEnd - t.SendStart,
+			Wait:    t.ReceiveHeadersEnd - t.SendEnd,
+			Receive: -1,
+		}
+		if n.finishedAt > 0 {
+			entry.Timings.Receive = max(0, float64(n.finishedAt)*1000-t.RequestTime*1000-t.ReceiveHeadersEnd)
+		}
+	}
+
+	return entry
+}
+
+func harHeaders(headers proto.NetworkHeaders) []harHeader {
+	out := make([]harHeader, 0, len(headers))
+	for name, value := range headers {
+		out = append(out, harHeader{Name: name, Value: value.Str()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func harQuery(raw string) []harHeader {
+	out := []harHeader{}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return out
+	}
+	for name, values := range parsed.Query() {
+		for _, value := range values {
+			out = append(out, harHeader{Name: name, Value: value})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func millis(t proto.MonotonicTime) float64 {
+	return float64(t) * 1000
+}
+
+func span(start, end float64) float64 {
+	if start < 0 || end < 0 {
+		return -1
+	}
+	return end - start
+}
+
+func firstNonNegative(values ...float64) float64 {
+	for _, v := range values {
+		if v >= 0 {
+			return v
+		}
+	}
+	return -1
+}
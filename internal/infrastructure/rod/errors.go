@@ -0,0 +1,116 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+)
+
+// navigationErrors maps Chrome net error codes, as reported by Page.navigate,
+// onto capture errors. Codes starting with a listed prefix match too.
+var navigationErrors = []struct {
+	prefix string
+	err    error
+}{
+	{"net::ERR_NAME_NOT_RESOLVED", errorEntity.ErrCaptureDNS},
+	{"net::ERR_NAME_RESOLUTION_FAILED", errorEntity.ErrCaptureDNS},
+	{"net::ERR_CERT_", errorEntity.ErrCaptureTLS},
+	{"net::ERR_SSL_", errorEntity.ErrCaptureTLS},
+	{"net::ERR_BAD_SSL_CLIENT_AUTH_CERT", errorEntity.ErrCaptureTLS},
+	{"net::ERR_TIMED_OUT", errorEntity.ErrCaptureTimeout},
+	{"net::ERR_CONNECTION_TIMED_OUT", errorEntity.ErrCaptureTimeout},
+	{"net::ERR_CONNECTION_", errorEntity.ErrCaptureConnection},
+	{"net::ERR_ADDRESS_UNREACHABLE", errorEntity.ErrCaptureConnection},
+	{"net::ERR_EMPTY_RESPONSE", errorEntity.ErrCaptureConnection},
+	{"net::ERR_TUNNEL_CONNECTION_FAILED", errorEntity.ErrCaptureConnection},
+	{"net::ERR_PROXY_CONNECTION_FAILED", errorEntity.ErrCaptureConnection},
+	{"net::ERR_INVALID_URL", errorEntity.ErrCaptureInvalidTarget},
+	{"net::ERR_UNKNOWN_URL_SCHEME", errorEntity.ErrCaptureInvalidTarget},
+	{"net::ERR_DISALLOWED_URL_SCHEME", errorEntity.ErrCaptureInvalidTarget},
+	{"net::ERR_BLOCKED_BY_", errorEntity.ErrCaptureInvalidTarget},
+}
+
+// classifyError wraps err with the capture error describing it. Errors that
+// are already classified, action failures and errors it does not recognise
+// are returned as is.
+func classifyError(err error) error {
+	if err == nil || isClassified(err) {
+		return err
+	}
+
+	var actionErr *ActionError
+	if errors.As(err, &actionErr) {
+		return err
+	}
+
+	var navErr *rod.NavigationError
+	if errors.As(err, &navErr) {
+		for _, known := range navigationErrors {
+			if strings.HasPrefix(navErr.Reason, known.prefix) {
+				return fmt.Errorf("%w: %w", known.err, err)
+			}
+		}
+		return fmt.Errorf("%w: %w", errorEntity.ErrCaptureNavigation, err)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", errorEntity.ErrCaptureTimeout, err)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("%w: %w", errorEntity.ErrCaptureCanceled, err)
+	}
+
+	if isBrowserGone(err) {
+		return fmt.Errorf("%w: %w", errorEntity.ErrCaptureBrowserCrashed, err)
+	}
+
+	return err
+}
+
+func isClassified(err error) bool {
+	for _, target := range []error{
+		errorEntity.ErrCaptureInvalidTarget,
+		errorEntity.ErrCaptureTargetStatus,
+		errorEntity.ErrCaptureDNS,
+		errorEntity.ErrCaptureTLS,
+		errorEntity.ErrCaptureConnection,
+		errorEntity.ErrCaptureNavigation,
+		errorEntity.ErrCaptureTimeout,
+		errorEntity.ErrCaptureBrowserCrashed,
+		errorEntity.ErrCaptureCanceled,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBrowserGone reports whether err means the DevTools connection or the
+// tab's renderer went away.
+func isBrowserGone(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var cdpErr *cdp.Error
+	if errors.As(err, &cdpErr) {
+		message := strings.ToLower(cdpErr.Message)
+		return strings.Contains(message, "target closed") || strings.Contains(message, "crashed")
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package rod
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IsolationIncognito = "incognito"
+	IsolationShared    = "shared"
+)
+
+// isolation decides whether each capture gets its own incognito browser
+// context, so cookies, storage and service workers never outlive it.
+var isolation = IsolationIncognito
+
+func SetIsolation(mode string) error {
+	if mode != IsolationIncognito && mode != IsolationShared {
+		return fmt.Errorf("invalid browser isolation %q: must be %s or %s", mode, IsolationIncognito, IsolationShared)
+	}
+	isolation = mode
+	return nil
+}
+
+// openPage opens a tab for a capture. With incognito isolation, or when a
+// proxy is given (Chrome only sets proxies per context), the tab gets a fresh
+// browser context; otherwise it shares the default context and the
+// launch-time proxy. The returned func closes the tab, disposes of the context
+// and stops proxy authentication.
+func openPage(proxy *Proxy) (*rod.Page, func(), error) {
+	var page *rod.Page
+	var contextID proto.BrowserBrowserContextID
+	var err error
+
+	if proxy != nil || isolation == IsolationIncognito {
+		var server string
+		if proxy != nil {
+			server = proxy.Server
+		} else {
+			proxy = getDefaultProxy()
+		}
+		page, contextID, err = getNewContextPage(server)
+	} else {
+		page, err = getNewPage()
+		proxy = getDefaultProxy()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stopAuth, err := handleProxyAuth(page, proxy)
+	if err != nil {
+		closePageContext(page, contextID)
+		return nil, nil, err
+	}
+
+	return page, func() {
+		stopAuth()
+		closePageContext(page, contextID)
+	}, nil
+}
+
+// getNewContextPage opens a tab in a new browser context. An empty
+// proxyServer keeps the browser's launch-time proxy.
+func getNewContextPage(proxyServer string) (*rod.Page, proto.BrowserBrowserContextID, error) {
+	browserMu.Lock()
+	defer browserMu.Unlock()
+
+	if browserInstance == nil {
+		return nil, "", fmt.Errorf("browser not initialized")
+	}
+
+	start := time.Now()
+	res, err := proto.TargetCreateBrowserContext{ProxyServer: proxyServer}.Call(browserInstance)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create browser context: %w", err)
+	}
+	observeContext("create", start)
+
+	isolated := *browserInstance
+	isolated.BrowserContextID = res.BrowserContextID
+
+	page, err := isolated.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		disposeContext(res.BrowserContextID)
+		return nil, "", fmt.Errorf("failed to create page: %w", err)
+	}
+	openPages = append(openPages, page)
+	trackOpenPages()
+
+	logrus.Debugf("Created browser context %s in %s", res.BrowserContextID, time.Since(start))
+	return page, res.BrowserContextID, nil
+}
+
+func closePageContext(page *rod.Page, contextID proto.BrowserBrowserContextID) {
+	ClosePage(page)
+	if contextID == "" {
+		return
+	}
+
+	browserMu.Lock()
+	defer browserMu.Unlock()
+
+	start := time.Now()
+	disposeContext(contextID)
+	observeContext("dispose", start)
+	logrus.Debugf("Disposed browser context %s in %s", contextID, time.Since(start))
+}
+
+// disposeContext must be called with browserMu held.
+func disposeContext(contextID proto.BrowserBrowserContextID) {
+	if browserInstance == nil {
+		return
+	}
+	if err := (proto.TargetDisposeBrowserContext{BrowserContextID: contextID}).Call(browserInstance); err != nil {
+		logrus.Warn("failed to dispose browser context: ", err)
+	}
+}
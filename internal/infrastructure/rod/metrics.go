@@ -0,0 +1,56 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordCancellation counts a capture that stopped because its context ended,
+// either at the deadline or because the client went away.
+func recordCancellation(ctx context.Context) {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		logging.FromContext(ctx).Warn("capture timed out")
+		metrics.CaptureCancellations.WithLabelValues("timeout").Inc()
+	case errors.Is(ctx.Err(), context.Canceled):
+		logging.FromContext(ctx).Warn("capture cancelled: client went away")
+		metrics.CaptureCancellations.WithLabelValues("client_gone").Inc()
+	}
+}
+
+// capturePhase times one phase of a capture as a span and, when the phase
+// succeeds, a CapturePhaseDuration sample.
+type capturePhase struct {
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+func startPhase(ctx context.Context, name string) *capturePhase {
+	_, span := tracing.Start(ctx, "rod."+name)
+	return &capturePhase{name: name, start: time.Now(), span: span}
+}
+
+func (p *capturePhase) end(err error) {
+	if err == nil {
+		metrics.CapturePhaseDuration.WithLabelValues(p.name).Observe(time.Since(p.start).Seconds())
+	}
+	tracing.End(p.span, err)
+}
+
+// observeContext records how long creating or disposing of a browser
+// context took.
+func observeContext(operation string, start time.Time) {
+	metrics.BrowserContextDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// trackOpenPages must be called with browserMu held after openPages changes.
+func trackOpenPages() {
+	metrics.BrowserOpenPages.Set(float64(len(openPages)))
+}
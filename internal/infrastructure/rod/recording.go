@@ -0,0 +1,263 @@
+package rod
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"sync"
+	"time"
+
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	recordingMaxWidth = 800
+	recordingQuality  = 80
+)
+
+type RecordingOptions struct {
+	URL        string
+	Width      int
+	Height     int
+	IsMobile   bool
+	Duration   time.Duration
+	Fps        int
+	AutoScroll bool
+}
+
+type RecordingResult struct {
+	Data        []byte
+	ContentType string
+	Frames      int
+	Metadata    PageMetadata
+}
+
+// screencastFrame keeps the JPEG as received. Frames are only decoded while
+// encoding, one at a time, so a recording holds compressed frames in memory.
+type screencastFrame struct {
+	data      []byte
+	timestamp time.Time
+}
+
+// autoScrollJS scrolls the document from top to bottom over the given number
+// of milliseconds.
+const autoScrollJS = `(duration) => {
+	const start = performance.now();
+	const distance = Math.max(0, document.documentElement.scrollHeight - window.innerHeight);
+	const step = (now) => {
+		const progress = Math.min(1, (now - start) / duration);
+		window.scrollTo(0, distance * progress);
+		if (progress < 1) {
+			requestAnimationFrame(step);
+		}
+	};
+	requestAnimationFrame(step);
+}`
+
+// RecordPage records the page from navigation through opt.Duration with
+// Page.startScreencast and encodes the frames as an animated GIF. Failures are
+// classified like CaptureScreenshot's, and the deadline is the capture timeout
+// on top of the recording duration.
+func RecordPage(ctx context.Context, browser *rod.Browser, opt RecordingOptions) (*RecordingResult, error) {
+	ctx, cancel := withCaptureTimeout(ctx, getCaptureTimeout()+opt.Duration)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "rod.RecordPage")
+
+	metrics.BrowserCapturesInFlight.Inc()
+	defer metrics.BrowserCapturesInFlight.Dec()
+
+	result, err := recordPage(ctx, opt)
+	if err != nil {
+		recordCancellation(ctx)
+		err = classifyError(err)
+		tracing.End(span, err)
+		return nil, err
+	}
+	span.End()
+	return result, nil
+}
+
+func recordPage(ctx context.Context, opt RecordingOptions) (*RecordingResult, error) {
+	_, err := InitBrowser()
+	if err != nil {
+		return nil, fmt.Errorf("%w: browser initialization failed: %w", errorEntity.ErrCaptureBrowserCrashed, err)
+	}
+
+	resetIdleTimer()
+
+	releaseTab, err := acquireTab(ctx)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	defer releaseTab()
+
+	page, closePage, err := openPage(nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get page: %w", errorEntity.ErrCaptureBrowserCrashed, err)
+	}
+	defer closePage()
+
+	page = page.Context(ctx)
+
+	if err := applyViewport(page, opt.Width, opt.Height, opt.IsMobile); err != nil {
+		return nil, err
+	}
+
+	// Frames arriving faster than the requested fps are dropped as they come
+	// in, since the screencast runs at the display's rate.
+	interval := frameInterval(opt.Fps)
+	var mu sync.Mutex
+	var frames []screencastFrame
+
+	listenCtx, stopListening := context.WithCancel(ctx)
+	defer stopListening()
+
+	wait := page.Context(listenCtx).EachEvent(func(e *proto.PageScreencastFrame) {
+		_ = proto.PageScreencastFrameAck{SessionID: e.SessionID}.Call(page)
+
+		timestamp := time.Now()
+		if e.Metadata != nil && e.Metadata.Timestamp > 0 {
+			timestamp = e.Metadata.Timestamp.Time()
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(frames) > 0 && timestamp.Sub(frames[len(frames)-1].timestamp) < interval {
+			return
+		}
+		frames = append(frames, screencastFrame{data: e.Data, timestamp: timestamp})
+	})
+	listenerDone := make(chan struct{})
+	go func() {
+		defer close(listenerDone)
+		wait()
+	}()
+
+	quality := recordingQuality
+	maxWidth := recordingMaxWidth
+	if err := (proto.PageStartScreencast{
+		Format:   proto.PageStartScreencastFormatJpeg,
+		Quality:  &quality,
+		MaxWidth: &maxWidth,
+	}).Call(page); err != nil {
+		return nil, fmt.Errorf("failed to start screencast: %w", err)
+	}
+
+	deadline := time.Now().Add(opt.Duration)
+
+	logging.FromContext(ctx).Infof("Recording %s for %s", opt.URL, opt.Duration)
+	if err := page.Navigate(opt.URL); err != nil {
+		return nil, fmt.Errorf("navigation failed: %w", err)
+	}
+
+	if err := page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("page load failed: %w", err)
+	}
+
+	if opt.AutoScroll {
+		remaining := time.Until(deadline)
+		if remaining > 0 {
+			if _, err := page.Eval(autoScrollJS, remaining.Milliseconds()); err != nil {
+				logging.FromContext(ctx).Warn("failed to start auto scroll: ", err)
+			}
+		}
+	}
+
+	select {
+	case <-time.After(time.Until(deadline)):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if err := (proto.PageStopScreencast{}).Call(page); err != nil {
+		logging.FromContext(ctx).Warn("failed to stop screencast: ", err)
+	}
+
+	metadata, err := extractMetadata(page)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to extract page metadata: ", err)
+	}
+
+	stopListening()
+	<-listenerDone
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("recording produced no frames")
+	}
+
+	data, count, err := encodeGIF(ctx, frames, interval, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Infof("Recording encoded with %d frames", count)
+	return &RecordingResult{
+		Data:        data,
+		ContentType: "image/gif",
+		Frames:      count,
+		Metadata:    metadata,
+	}, nil
+}
+
+func frameInterval(fps int) time.Duration {
+	if fps <= 0 {
+		fps = 1
+	}
+	return time.Second / time.Duration(fps)
+}
+
+// encodeGIF decodes the frames one at a time and encodes them with their
+// real timing, holding the last one until end. Frames that fail to decode are
+// skipped. Each frame is quantised to the Plan 9 palette on a canvas the size
+// of the first frame.
+func encodeGIF(ctx context.Context, frames []screencastFrame, interval time.Duration, end time.Time) ([]byte, int, error) {
+	anim := &gif.GIF{}
+	var canvas image.Rectangle
+	for i, frame := range frames {
+		img, err := jpeg.Decode(bytes.NewReader(frame.data))
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to decode screencast frame: ", err)
+			continue
+		}
+		if len(anim.Image) == 0 {
+			canvas = image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy())
+		}
+
+		paletted := image.NewPaletted(canvas, palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, canvas, img, img.Bounds().Min)
+
+		next := end
+		if i+1 < len(frames) {
+			next = frames[i+1].timestamp
+		}
+		delay := max(interval, next.Sub(frame.timestamp))
+
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, max(2, int(delay/(10*time.Millisecond))))
+	}
+
+	if len(anim.Image) == 0 {
+		return nil, 0, fmt.Errorf("recording produced no decodable frames")
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, 0, fmt.Errorf("failed to encode gif: %w", err)
+	}
+
+	return buf.Bytes(), len(anim.Image), nil
+}
@@ -3,28 +3,131 @@ package rod
 import (
 	"context"
 	"fmt"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/devices"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"html"
+	"io"
+	"strings"
 	"sync"
 	"time"
 )
 
+// ScreenshotOptions describes a capture. When HTML is set it is rendered
+// instead of navigating to URL, resolving relative links against BaseURL.
+// Actions run in order once the page has loaded, before the delay. Proxy
+// routes the capture through a proxy instead of the deployment default.
+// FailOnHTTPError fails URL captures whose document responds 4xx or 5xx
+// instead of capturing the error page. Timeout bounds the whole capture and
+// defaults to the deployment's capture timeout.
 type ScreenshotOptions struct {
-	URL          string
-	Width        int
-	Height       int
-	FullPage     bool
-	DelaySeconds int
-	IsMobile     bool
+	URL             string
+	HTML            string
+	BaseURL         string
+	Width           int
+	Height          int
+	FullPage        bool
+	DelaySeconds    int
+	IsMobile        bool
+	Archive         ArchiveOptions
+	Debug           bool
+	Trace           bool
+	PDF             bool
+	Actions         []Action
+	Proxy           *Proxy
+	FailOnHTTPError bool
+	Timeout         time.Duration
 }
 
+// ArchiveOptions selects the document snapshots stored next to the image.
+type ArchiveOptions struct {
+	HTML  bool
+	MHTML bool
+	Text  bool
+}
+
+const (
+	ArtifactHTML  = "html"
+	ArtifactMHTML = "mhtml"
+	ArtifactText  = "text"
+)
+
+// Artifact is a file produced by a capture in addition to the image.
+type Artifact struct {
+	Kind        string
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// PageMetadata describes the captured document. StatusCode is zero when the
+// browser does not report the main document's response status.
+type PageMetadata struct {
+	Title        string            `json:"title"`
+	Description  string            `json:"description"`
+	OpenGraph    map[string]string `json:"openGraph"`
+	Twitter      map[string]string `json:"twitter"`
+	CanonicalURL string            `json:"canonicalUrl"`
+	FinalURL     string            `json:"finalUrl"`
+	StatusCode   int               `json:"statusCode"`
+	Favicon      string            `json:"favicon"`
+}
+
+type CaptureResult struct {
+	Image     []byte
+	Metadata  PageMetadata
+	Artifacts []Artifact
+}
+
+const extractMetadataJS = `() => {
+	const content = (selector) => {
+		const el = document.querySelector(selector);
+		return el ? (el.getAttribute('content') || '').trim() : '';
+	};
+	const href = (rel) => {
+		const el = document.querySelector('link[rel~="' + rel + '" i][href]');
+		return el ? el.href : '';
+	};
+	const collect = (prefix) => {
+		const out = {};
+		document.querySelectorAll('meta[property^="' + prefix + '"], meta[name^="' + prefix + '"]').forEach((el) => {
+			const key = el.getAttribute('property') || el.getAttribute('name');
+			if (key && !(key in out)) {
+				out[key] = (el.getAttribute('content') || '').trim();
+			}
+		});
+		return out;
+	};
+	let favicon = href('icon') || href('apple-touch-icon');
+	if (!favicon && /^https?:$/.test(location.protocol)) {
+		favicon = location.origin + '/favicon.ico';
+	}
+	const nav = performance.getEntriesByType('navigation')[0];
+	return {
+		title: document.title || '',
+		description: content('meta[name="description" i]'),
+		openGraph: collect('og:'),
+		twitter: collect('twitter:'),
+		canonicalUrl: href('canonical'),
+		finalUrl: location.href,
+		statusCode: nav && nav.responseStatus ? nav.responseStatus : 0,
+		favicon: favicon,
+	};
+}`
+
 var (
 	browserInstance *rod.Browser
 	browserMu       sync.Mutex
 	tabLimit        = 5
+	tabSlots        = make(chan struct{}, 5)
 	openPages       []*rod.Page
 	idleTimeout     = 2 * time.Minute
 	idleTimer       *time.Timer
@@ -38,8 +141,30 @@ func SetHeadless(h bool) {
 	headless = h
 }
 
+// SetTabLimit caps the tabs open at once. Captures beyond the limit wait for
+// a tab to close; 0 removes the limit.
 func SetTabLimit(limit int) {
 	tabLimit = limit
+	tabSlots = nil
+	if limit > 0 {
+		tabSlots = make(chan struct{}, limit)
+	}
+}
+
+// acquireTab waits for a free tab slot until ctx is done. Live tabs are
+// never closed to make room, since each belongs to a capture in flight. The
+// returned func gives the slot back.
+func acquireTab(ctx context.Context) (func(), error) {
+	if tabSlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case tabSlots <- struct{}{}:
+		return func() { <-tabSlots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no free tab within the tab limit of %d: %w", tabLimit, ctx.Err())
+	}
 }
 
 func SetIdleTimeout(timeout time.Duration) {
@@ -67,6 +192,9 @@ func startBrowser() error {
 	}
 
 	l := launcher.New().Headless(headless)
+	if proxy := getDefaultProxy(); proxy != nil {
+		l = l.Proxy(proxy.Server)
+	}
 	url, err := l.Launch()
 	if err != nil {
 		return fmt.Errorf("failed to launch browser: %w", err)
@@ -80,6 +208,7 @@ func startBrowser() error {
 
 	browserInstance = browser
 	openPages = nil
+	trackOpenPages()
 	return nil
 }
 
@@ -113,6 +242,7 @@ func RestartBrowser() {
 		cleanupBrowserResources()
 	}
 
+	metrics.BrowserRestarts.Inc()
 	if err := startBrowser(); err != nil {
 		initErr = err
 		return
@@ -120,11 +250,60 @@ func RestartBrowser() {
 	startIdleTimer()
 }
 
+// Shutdown stops the idle restart and closes every tab and the browser.
+// Captures still running, and any started afterwards, fail as if the browser
+// had crashed.
+func Shutdown() {
+	timerMu.Lock()
+	if idleTimer != nil {
+		idleTimer.Stop()
+		idleTimer = nil
+	}
+	timerMu.Unlock()
+
+	browserMu.Lock()
+	defer browserMu.Unlock()
+
+	if browserInstance != nil {
+		cleanupBrowserResources()
+	}
+	initErr = fmt.Errorf("browser is shut down")
+	logrus.Info("Browser closed")
+}
+
+// PoolStatus reports the open tabs against the tab limit.
+type PoolStatus struct {
+	OpenPages int `json:"open_pages"`
+	TabLimit  int `json:"tab_limit"`
+}
+
+// Ping checks that the browser is running and answers CDP calls. The lock is
+// not held during the call, so a slow browser does not stall captures.
+func Ping(ctx context.Context) (*PoolStatus, error) {
+	browserMu.Lock()
+	browser, err := browserInstance, initErr
+	status := &PoolStatus{OpenPages: len(openPages), TabLimit: tabLimit}
+	browserMu.Unlock()
+
+	if browser == nil {
+		if err != nil {
+			return status, fmt.Errorf("browser not running: %w", err)
+		}
+		return status, fmt.Errorf("browser not running")
+	}
+
+	if _, err := (proto.BrowserGetVersion{}).Call(browser.Context(ctx)); err != nil {
+		return status, fmt.Errorf("browser not responding: %w", err)
+	}
+	return status, nil
+}
+
 func cleanupBrowserResources() {
 	for _, p := range openPages {
 		p.Close()
 	}
 	openPages = nil
+	trackOpenPages()
 
 	if err := browserInstance.Close(); err != nil {
 		logrus.Warnf("Error closing browser: %v", err)
@@ -140,17 +319,12 @@ func getNewPage() (*rod.Page, error) {
 		return nil, fmt.Errorf("browser not initialized")
 	}
 
-	if len(openPages) >= tabLimit && tabLimit > 0 {
-		oldest := openPages[0]
-		oldest.Close()
-		openPages = openPages[1:]
-	}
-
 	page, err := browserInstance.Page(proto.TargetCreateTarget{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
 	openPages = append(openPages, page)
+	trackOpenPages()
 	return page, nil
 }
 
@@ -167,37 +341,324 @@ func ClosePage(page *rod.Page) {
 	for i, p := range openPages {
 		if p == page {
 			openPages = append(openPages[:i], openPages[i+1:]...)
+			trackOpenPages()
 			break
 		}
 	}
 }
 
-func CaptureScreenshot(ctx context.Context, browser *rod.Browser, opt ScreenshotOptions) ([]byte, error) {
+// CaptureScreenshot captures the page described by opt. Failures are wrapped
+// with the matching capture error from the domain error package.
+func CaptureScreenshot(ctx context.Context, browser *rod.Browser, opt ScreenshotOptions) (*CaptureResult, error) {
+	ctx, cancel := withCaptureTimeout(ctx, opt.Timeout)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "rod.CaptureScreenshot", trace.WithAttributes(
+		attribute.Int("capture.width", opt.Width),
+		attribute.Int("capture.height", opt.Height),
+		attribute.Bool("capture.full_page", opt.FullPage),
+		attribute.Int("capture.actions", len(opt.Actions)),
+	))
+
+	metrics.BrowserCapturesInFlight.Inc()
+	defer metrics.BrowserCapturesInFlight.Dec()
+
+	result, err := captureScreenshot(ctx, opt)
+	if err != nil {
+		recordCancellation(ctx)
+		err = classifyError(err)
+		tracing.End(span, err)
+		return nil, err
+	}
+	span.End()
+	return result, nil
+}
+
+func captureScreenshot(ctx context.Context, opt ScreenshotOptions) (*CaptureResult, error) {
 	_, err := InitBrowser()
 	if err != nil {
-		return nil, fmt.Errorf("browser initialization failed: %w", err)
+		return nil, fmt.Errorf("%w: browser initialization failed: %w", errorEntity.ErrCaptureBrowserCrashed, err)
 	}
 
 	resetIdleTimer()
 
-	page, err := getNewPage()
+	releaseTab, err := acquireTab(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get page: %w", err)
+		return nil, classifyError(err)
 	}
-	defer ClosePage(page)
+	defer releaseTab()
+
+	page, closePage, err := openPage(opt.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get page: %w", errorEntity.ErrCaptureBrowserCrashed, err)
+	}
+	defer closePage()
 
 	page = page.Context(ctx)
 
-	if err := page.Navigate(opt.URL); err != nil {
-		return nil, fmt.Errorf("navigation failed: %w", err)
+	var recorder *debugRecorder
+	if opt.Debug {
+		recorder = startDebugRecorder(page)
+		defer recorder.stop()
+	}
+
+	if opt.Trace {
+		if err := startTrace(page); err != nil {
+			return nil, err
+		}
+	}
+
+	navigate := startPhase(ctx, metrics.PhaseNavigate)
+	err = navigatePage(page, opt)
+	navigate.end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.FailOnHTTPError && opt.HTML == "" {
+		if status := navigationStatus(page); status >= 400 {
+			return nil, fmt.Errorf("%w: status %d", errorEntity.ErrCaptureTargetStatus, status)
+		}
+	}
+
+	wait := startPhase(ctx, metrics.PhaseWait)
+	err = waitForPage(ctx, page, opt)
+	wait.end(err)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := extractMetadata(page)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to extract page metadata: ", err)
+	}
+
+	render := startPhase(ctx, metrics.PhaseRender)
+	buf, err := renderPage(page, opt)
+	render.end(err)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("Screenshot taken successfully")
+
+	artifacts, err := captureArchives(page, opt.Archive)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to archive page: ", err)
+		return nil, err
+	}
+
+	if opt.Trace {
+		trace, err := stopTrace(page)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to record trace: ", err)
+			return nil, err
+		}
+		artifacts = append(artifacts, *trace)
+	}
+
+	if recorder != nil {
+		debugArtifacts, err := recorder.finish()
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to record debug artifacts: ", err)
+			return nil, err
+		}
+		artifacts = append(artifacts, debugArtifacts...)
+	}
+
+	return &CaptureResult{
+		Image:     buf,
+		Metadata:  metadata,
+		Artifacts: artifacts,
+	}, nil
+}
+
+func navigatePage(page *rod.Page, opt ScreenshotOptions) error {
+	if err := loadContent(page, opt); err != nil {
+		return err
 	}
 
 	if err := page.WaitLoad(); err != nil {
-		return nil, fmt.Errorf("page load failed: %w", err)
+		return fmt.Errorf("page load failed: %w", err)
+	}
+	return nil
+}
+
+// waitForPage applies the viewport, runs the actions and sleeps for the
+// requested delay.
+func waitForPage(ctx context.Context, page *rod.Page, opt ScreenshotOptions) error {
+	if err := applyViewport(page, opt.Width, opt.Height, opt.IsMobile); err != nil {
+		return err
 	}
 
-	if opt.IsMobile {
-		if opt.Width > 0 && opt.Height > 0 {
+	logging.FromContext(ctx).Info("Waiting for page load after emulation/viewport")
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("page load failed: %w", err)
+	}
+	logging.FromContext(ctx).Info("Page loaded after emulation/viewport")
+
+	if err := runActions(page, opt.Actions); err != nil {
+		logging.FromContext(ctx).Warn("capture action failed: ", err)
+		return err
+	}
+
+	if opt.DelaySeconds > 0 {
+		logging.FromContext(ctx).Infof("Waiting for %d seconds", opt.DelaySeconds)
+		select {
+		case <-time.After(time.Duration(opt.DelaySeconds) * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		logging.FromContext(ctx).Info("Delay complete")
+	}
+	return nil
+}
+
+func renderPage(page *rod.Page, opt ScreenshotOptions) ([]byte, error) {
+	var buf []byte
+	var err error
+
+	if opt.PDF {
+		logging.FromContext(page.GetContext()).Info("Printing page to PDF")
+		buf, err = printPDF(page)
+	} else if opt.FullPage {
+		logging.FromContext(page.GetContext()).Info("Taking full page screenshot")
+		buf, err = page.Screenshot(true, nil)
+	} else {
+		logging.FromContext(page.GetContext()).Info("Taking viewport screenshot")
+		buf, err = page.Screenshot(false, nil)
+	}
+
+	if err != nil {
+		logging.FromContext(page.GetContext()).Error("failed to take screenshot: ", err)
+		return nil, fmt.Errorf("failed to take screenshot: %w", err)
+	}
+	return buf, nil
+}
+
+// navigationStatus returns the main document's HTTP status, or zero when the
+// browser does not report it.
+func navigationStatus(page *rod.Page) int {
+	res, err := page.Eval(`() => {
+		const nav = performance.getEntriesByType('navigation')[0];
+		return nav && nav.responseStatus ? nav.responseStatus : 0;
+	}`)
+	if err != nil {
+		logging.FromContext(page.GetContext()).Warn("failed to read navigation status: ", err)
+		return 0
+	}
+	return res.Value.Int()
+}
+
+func captureArchives(page *rod.Page, opt ArchiveOptions) ([]Artifact, error) {
+	var artifacts []Artifact
+
+	if opt.HTML {
+		html, err := page.HTML()
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive html: %w", err)
+		}
+		artifacts = append(artifacts, Artifact{
+			Kind:        ArtifactHTML,
+			Filename:    "snapshot.html",
+			ContentType: "text/html; charset=utf-8",
+			Data:        []byte(html),
+		})
+	}
+
+	if opt.MHTML {
+		snapshot, err := proto.PageCaptureSnapshot{Format: proto.PageCaptureSnapshotFormatMhtml}.Call(page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive mhtml: %w", err)
+		}
+		artifacts = append(artifacts, Artifact{
+			Kind:        ArtifactMHTML,
+			Filename:    "snapshot.mhtml",
+			ContentType: "multipart/related",
+			Data:        []byte(snapshot.Data),
+		})
+	}
+
+	if opt.Text {
+		res, err := page.Eval(`() => document.body ? document.body.innerText : ''`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive text: %w", err)
+		}
+		artifacts = append(artifacts, Artifact{
+			Kind:        ArtifactText,
+			Filename:    "snapshot.txt",
+			ContentType: "text/plain; charset=utf-8",
+			Data:        []byte(res.Value.Str()),
+		})
+	}
+
+	return artifacts, nil
+}
+
+func printPDF(page *rod.Page) ([]byte, error) {
+	stream, err := page.PDF(&proto.PagePrintToPDF{
+		PrintBackground:   true,
+		PreferCSSPageSize: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	return io.ReadAll(stream)
+}
+
+// loadContent navigates to the target URL, or renders the given HTML in a
+// blank page.
+func loadContent(page *rod.Page, opt ScreenshotOptions) error {
+	if opt.HTML == "" {
+		if err := page.Navigate(opt.URL); err != nil {
+			return fmt.Errorf("navigation failed: %w", err)
+		}
+		return nil
+	}
+
+	if err := page.Navigate("about:blank"); err != nil {
+		return fmt.Errorf("navigation failed: %w", err)
+	}
+
+	if err := page.SetDocumentContent(withBaseURL(opt.HTML, opt.BaseURL)); err != nil {
+		return fmt.Errorf("failed to set document content: %w", err)
+	}
+	return nil
+}
+
+// withBaseURL adds a <base> element to the document head so relative asset
+// URLs resolve against baseURL.
+func withBaseURL(document string, baseURL string) string {
+	if baseURL == "" {
+		return document
+	}
+
+	base := fmt.Sprintf(`<base href="%s">`, html.EscapeString(baseURL))
+
+	lower := strings.ToLower(document)
+	if i := strings.Index(lower, "<head"); i >= 0 {
+		if end := strings.Index(lower[i:], ">"); end >= 0 {
+			at := i + end + 1
+			return document[:at] + base + document[at:]
+		}
+	}
+
+	if i := strings.Index(lower, "<!doctype"); i >= 0 {
+		if end := strings.Index(lower[i:], ">"); end >= 0 {
+			at := i + end + 1
+			return document[:at] + base + document[at:]
+		}
+	}
+
+	return base + document
+}
+
+// applyViewport emulates a mobile device or sets a fixed desktop viewport.
+// Without a size or mobile flag the browser default is kept.
+func applyViewport(page *rod.Page, width int, height int, isMobile bool) error {
+	if isMobile {
+		if width > 0 && height > 0 {
 			device := devices.Device{
 				Title: "Custom Mobile",
 				Screen: struct {
@@ -208,60 +669,54 @@ func CaptureScreenshot(ctx context.Context, browser *rod.Browser, opt Screenshot
 					DevicePixelRatio: 2.0,
 					Horizontal:       devices.ScreenSize{},
 					Vertical: devices.ScreenSize{
-						Width:  opt.Width,
-						Height: opt.Height,
+						Width:  width,
+						Height: height,
 					},
 				},
 				UserAgent: devices.IPhoneX.UserAgent,
 			}
 
-			logrus.Info("Emulating custom mobile device")
+			logging.FromContext(page.GetContext()).Info("Emulating custom mobile device")
 			if err := page.Emulate(device); err != nil {
-				logrus.Error("failed to emulate custom mobile: ", err)
-				return nil, fmt.Errorf("failed to emulate custom mobile: %w", err)
+				logging.FromContext(page.GetContext()).Error("failed to emulate custom mobile: ", err)
+				return fmt.Errorf("failed to emulate custom mobile: %w", err)
 			}
 
-			logrus.Info("Emulate custom mobile complete")
+			logging.FromContext(page.GetContext()).Info("Emulate custom mobile complete")
 		} else {
-			logrus.Info("Emulating default mobile device (iPhoneX)")
+			logging.FromContext(page.GetContext()).Info("Emulating default mobile device (iPhoneX)")
 			if err := page.Emulate(devices.IPhoneX); err != nil {
-				logrus.Error("failed to emulate default mobile: ", err)
-				return nil, fmt.Errorf("failed to emulate default mobile: %w", err)
+				logging.FromContext(page.GetContext()).Error("failed to emulate default mobile: ", err)
+				return fmt.Errorf("failed to emulate default mobile: %w", err)
 			}
-			logrus.Info("Emulate default mobile complete")
+			logging.FromContext(page.GetContext()).Info("Emulate default mobile complete")
 		}
-	} else if opt.Width > 0 && opt.Height > 0 {
-		logrus.Infof("Setting viewport to %dx%d", opt.Width, opt.Height)
-		page.MustSetViewport(opt.Width, opt.Height, 1.0, false)
-		logrus.Info("Viewport set")
+	} else if width > 0 && height > 0 {
+		logging.FromContext(page.GetContext()).Infof("Setting viewport to %dx%d", width, height)
+		if err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+			Width:             width,
+			Height:            height,
+			DeviceScaleFactor: 1.0,
+		}); err != nil {
+			return fmt.Errorf("failed to set viewport: %w", err)
+		}
+		logging.FromContext(page.GetContext()).Info("Viewport set")
 	}
 
-	logrus.Info("Waiting for page load after emulation/viewport")
-	page.MustWaitLoad()
-	logrus.Info("Page loaded after emulation/viewport")
-
-	if opt.DelaySeconds > 0 {
-		logrus.Infof("Waiting for %d seconds", opt.DelaySeconds)
-		time.Sleep(time.Duration(opt.DelaySeconds) * time.Second)
-		logrus.Info("Delay complete")
-	}
+	return nil
+}
 
-	var buf []byte
-	var errors error
+func extractMetadata(page *rod.Page) (PageMetadata, error) {
+	var metadata PageMetadata
 
-	if opt.FullPage {
-		logrus.Info("Taking full page screenshot")
-		buf, errors = page.Screenshot(true, nil)
-	} else {
-		logrus.Info("Taking viewport screenshot")
-		buf, errors = page.Screenshot(false, nil)
+	res, err := page.Eval(extractMetadataJS)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to evaluate metadata script: %w", err)
 	}
 
-	if errors != nil {
-		logrus.Error("failed to take screenshot: ", errors)
-		return nil, fmt.Errorf("failed to take screenshot: %w", errors)
+	if err := res.Value.Unmarshal(&metadata); err != nil {
+		return metadata, fmt.Errorf("failed to decode page metadata: %w", err)
 	}
 
-	logrus.Info("Screenshot taken successfully")
-	return buf, nil
+	return metadata, nil
 }
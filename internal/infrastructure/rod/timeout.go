@@ -0,0 +1,53 @@
+package rod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	captureTimeout    = 30 * time.Second
+	maxCaptureTimeout = 2 * time.Minute
+	timeoutMu         sync.RWMutex
+)
+
+// SetCaptureTimeouts sets the deadline applied to captures that do not ask
+// for one and the largest deadline a request may ask for.
+func SetCaptureTimeouts(defaultTimeout time.Duration, maxTimeout time.Duration) error {
+	if defaultTimeout <= 0 || maxTimeout <= 0 {
+		return errors.New("capture timeouts must be positive")
+	}
+	if defaultTimeout > maxTimeout {
+		return fmt.Errorf("default capture timeout %s exceeds the maximum %s", defaultTimeout, maxTimeout)
+	}
+
+	timeoutMu.Lock()
+	defer timeoutMu.Unlock()
+	captureTimeout = defaultTimeout
+	maxCaptureTimeout = maxTimeout
+	return nil
+}
+
+func MaxCaptureTimeout() time.Duration {
+	timeoutMu.RLock()
+	defer timeoutMu.RUnlock()
+	return maxCaptureTimeout
+}
+
+func getCaptureTimeout() time.Duration {
+	timeoutMu.RLock()
+	defer timeoutMu.RUnlock()
+	return captureTimeout
+}
+
+// withCaptureTimeout bounds ctx by timeout, or by the default capture timeout
+// when timeout is zero.
+func withCaptureTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = getCaptureTimeout()
+	}
+	return context.WithTimeout(ctx, timeout)
+}
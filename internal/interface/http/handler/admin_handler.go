@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	admin *usecase.AdminUseCase
+}
+
+func NewAdminHandler(admin *usecase.AdminUseCase) *AdminHandler {
+	return &AdminHandler{
+		admin: admin,
+	}
+}
+
+// GetConfig godoc
+// @Summary      Get Config
+// @Description  Get the effective configuration and where each setting came from, with secrets redacted. Only users listed in ADMIN_EMAILS may call it.
+// @Tags         Admin
+// @Produce      json
+// @Success 200 {object} response.Response{data=dto.ConfigResponse} "Successfully get config"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 403 {object} response.ErrorResponse "forbidden"
+// @Security BearerAuth
+// @Router       /admin/config [get]
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	response.OK(c, "successfully get config", h.admin.Config())
+}
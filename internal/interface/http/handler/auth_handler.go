@@ -8,11 +8,11 @@ import (
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/util"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/markbates/goth/gothic"
-	"github.com/sirupsen/logrus"
 )
 
 type AuthHandler struct {
@@ -130,7 +130,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	err = h.auth.Logout(&body, &user, accessToken)
+	err = h.auth.Logout(&body, &user, accessToken, c.Request.Context())
 	if err != nil {
 		if util.ErrorInList(err, errorEntity.ErrInvalidToken, errorEntity.ErrInvalidUser, errorEntity.ErrTokenAlreadyBlacklisted) {
 			response.Unauthorized(c, "unauthorized", err)
@@ -345,7 +345,7 @@ func (h *AuthHandler) GoogleVerifyToken(c *gin.Context) {
 	appToken, err := h.auth.GoogleVerify(c.Request.Context(), body.Token)
 	if err != nil {
 		response.Unauthorized(c, "authentication failed", err)
-		logrus.Error("GoogleVerifyToken error: ", err)
+		logging.FromContext(c.Request.Context()).Error("GoogleVerifyToken error: ", err)
 		return
 	}
 
@@ -412,7 +412,7 @@ func (h *AuthHandler) GenerateApiKey(c *gin.Context) {
 		return
 	}
 
-	apiKey, err := h.auth.GenerateApiKey(&user)
+	apiKey, err := h.auth.GenerateApiKey(&user, c.Request.Context())
 	if err != nil {
 		if util.ErrorInList(err, errorEntity.ErrUserNotFound) {
 			response.Unauthorized(c, "unauthorized", err)
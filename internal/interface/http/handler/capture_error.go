@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// statusClientClosedRequest is the de facto status for requests the client
+// abandoned; it only ends up in logs since nobody is left to read it.
+const statusClientClosedRequest = 499
+
+// captureErrors maps capture failures to a status and a stable code. 4xx
+// codes mean the request or target needs to change; 502, 503 and 504 are
+// worth retrying, and 501 means the server is not set up for the feature.
+var captureErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{errorEntity.ErrCaptureInvalidTarget, http.StatusBadRequest, "invalid_target"},
+	{errorEntity.ErrCaptureTargetStatus, http.StatusUnprocessableEntity, "target_http_error"},
+	{errorEntity.ErrCaptureAction, http.StatusUnprocessableEntity, "action_failed"},
+	{errorEntity.ErrCaptureDNS, http.StatusBadGateway, "dns_failure"},
+	{errorEntity.ErrCaptureTLS, http.StatusBadGateway, "tls_error"},
+	{errorEntity.ErrCaptureConnection, http.StatusBadGateway, "connection_failed"},
+	{errorEntity.ErrCaptureNavigation, http.StatusBadGateway, "navigation_failed"},
+	{errorEntity.ErrCaptureTimeout, http.StatusGatewayTimeout, "timeout"},
+	{errorEntity.ErrCaptureBrowserCrashed, http.StatusServiceUnavailable, "browser_unavailable"},
+	{errorEntity.ErrCaptureCanceled, statusClientClosedRequest, "client_closed_request"},
+	{errorEntity.ErrSigningNotConfigured, http.StatusNotImplemented, "signing_not_configured"},
+}
+
+// respondCaptureError writes the response for a classified capture failure
+// and reports whether err was one.
+func respondCaptureError(c *gin.Context, err error) bool {
+	for _, known := range captureErrors {
+		if errors.Is(err, known.err) {
+			response.ErrorWithCode(c, known.status, known.code, known.err.Error(), err)
+			return true
+		}
+	}
+	return false
+}
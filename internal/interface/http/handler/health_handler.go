@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	health *usecase.HealthUseCase
+}
+
+func NewHealthHandler(health *usecase.HealthUseCase) *HealthHandler {
+	return &HealthHandler{
+		health: health,
+	}
+}
+
+// Liveness reports that the process is up. It checks no dependencies, so an
+// outage elsewhere does not get the container restarted. It is served at the
+// root rather than under the API base path, so it is not in the Swagger docs.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.String(http.StatusOK, "ok")
+}
+
+// Readiness reports each dependency's status and latency, and fails while
+// the service is starting or draining for shutdown.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	data, ready := h.health.Readiness(c.Request.Context())
+	if !ready {
+		response.Success(c, http.StatusServiceUnavailable, "not ready", data)
+		return
+	}
+
+	response.OK(c, "ready", data)
+}
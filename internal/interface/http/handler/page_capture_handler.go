@@ -4,14 +4,17 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/tracing"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/util"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type PageCaptureHandler struct {
@@ -26,18 +29,32 @@ func NewPageCaptureHandler(pageCapture *usecase.PageCaptureUseCase) *PageCapture
 
 // PageCapture godoc
 // @Summary      Get Page Capture
-// @Description  Get Page Capture
+// @Description  Get Page Capture. With `viewports` the page is captured once per viewport and `output` selects a ZIP (default), a contact sheet PNG or the batch listing as JSON.
 // @Tags         Page Capture
 // @Accept       json
 // @Produce      json
+// @Produce      image/png
+// @Produce      application/zip
 // @Param        key      path  string                  true  "Key for Page Capture"
 // @Param        request  body  dto.PageCaptureRequest  true  "Page Capture Request"
 // @Success 200 {file} binary "Successfully get Page Capture image"
+// @Header  200 {string} X-Batch-Id "Batch ID shared by the history rows of a multi-viewport capture"
+// @Header  200 {string} X-Cache "HIT when served from the capture cache, MISS otherwise"
+// @Header  200 {string} X-Capture-Id "ID of the history row created for this capture (cache misses only)"
 // @Failure 400 {object} response.ErrorResponse "invalid request"
 // @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 400 {object} response.ErrorResponse "invalid_target: the url cannot be loaded"
+// @Failure 422 {object} response.ErrorResponse "action_failed (the error names the step index) or target_http_error"
 // @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Failure 502 {object} response.ErrorResponse "dns_failure, tls_error, connection_failed or navigation_failed"
+// @Failure 503 {object} response.ErrorResponse "browser_unavailable"
+// @Failure 504 {object} response.ErrorResponse "timeout"
 // @Router       /page-capture/{key} [post]
 func (h *PageCaptureHandler) PageCapture(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "PageCaptureHandler.PageCapture")
+	defer span.End()
+	c.Request = c.Request.WithContext(ctx)
+
 	body, err := util.GetBody[dto.PageCaptureRequest](c, "body")
 	if err != nil {
 		response.BadRequest(c, "invalid request", err)
@@ -50,18 +67,86 @@ func (h *PageCaptureHandler) PageCapture(c *gin.Context) {
 		return
 	}
 
-	data, err := h.pageCapture.PageCapture(&body, key, c.Request.Context())
+	data, err := h.pageCapture.PageCapture(&body, key, ctx)
 	if err != nil {
+		tracing.RecordError(span, err)
 		if util.ErrorInList(err, errorEntity.ErrInvalidCredentials, errorEntity.ErrUserNotFound, errorEntity.ErrCloudinaryUpload) {
 			response.Unauthorized(c, "unauthorized", err)
+		} else if util.ErrorInList(err, errorEntity.ErrInvalidRequest) {
+			response.BadRequest(c, "invalid request", err)
+		} else if respondCaptureError(c, err) {
+			return
+		} else {
+			response.InternalServerError(c, err)
+		}
+		return
+	}
+
+	if data.Batch != nil {
+		c.Header("X-Batch-Id", data.Batch.BatchID.String())
+		if data.Content == nil {
+			response.OK(c, "successfully capture viewports", data.Batch)
+			return
+		}
+	}
+
+	if data.CacheHit {
+		c.Header("X-Cache", "HIT")
+	} else {
+		c.Header("X-Cache", "MISS")
+		if data.Batch == nil {
+			c.Header("X-Capture-Id", data.CaptureID.String())
+		}
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, data.Filename))
+	c.Data(http.StatusOK, data.ContentType, data.Content)
+}
+
+// PageRecording godoc
+// @Summary      Record Page Load
+// @Description  Record a page from navigation through the given duration, optionally auto-scrolling, as an animated GIF
+// @Tags         Page Capture
+// @Accept       json
+// @Produce      image/gif
+// @Param        key      path  string                    true  "Key for Page Capture"
+// @Param        request  body  dto.PageRecordingRequest  true  "Page Recording Request"
+// @Success 200 {file} binary "Successfully record page"
+// @Header  200 {string} X-Capture-Id "ID of the history row created for this recording"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Failure 502 {object} response.ErrorResponse "dns_failure, tls_error, connection_failed or navigation_failed"
+// @Failure 503 {object} response.ErrorResponse "browser_unavailable"
+// @Failure 504 {object} response.ErrorResponse "timeout"
+// @Router       /page-capture/{key}/recording [post]
+func (h *PageCaptureHandler) PageRecording(c *gin.Context) {
+	body, err := util.GetBody[dto.PageRecordingRequest](c, "body")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	key := c.Param("key")
+	if key == "" {
+		response.BadRequest(c, "missing required param parameter 'key'", nil)
+		return
+	}
+
+	data, err := h.pageCapture.PageRecording(&body, key, c.Request.Context())
+	if err != nil {
+		if util.ErrorInList(err, errorEntity.ErrInvalidCredentials, errorEntity.ErrUserNotFound) {
+			response.Unauthorized(c, "unauthorized", err)
+		} else if respondCaptureError(c, err) {
+			return
 		} else {
 			response.InternalServerError(c, err)
 		}
 		return
 	}
 
+	c.Header("X-Capture-Id", data.CaptureID.String())
 	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, data.Filename))
-	c.Data(http.StatusOK, "image/png", data.Content)
+	c.Data(http.StatusOK, data.ContentType, data.Content)
 }
 
 // GetPageCapture godoc
@@ -70,12 +155,22 @@ func (h *PageCaptureHandler) PageCapture(c *gin.Context) {
 // @Tags         Page Capture
 // @Accept       json
 // @Produce      json
-// @Param        search    query     string  false  "Search keyword (matches url, image_path, or public_id)"
-// @Param        orderBy   query     string  false  "Order by field (default: created_at)"
-// @Param        sort      query     string  false  "Sort direction: asc or desc (default: desc)"
-// @Param        page      query     int     false  "Page number (default: 1)"
-// @Param        fullPage  query     bool    false  "Filter by full_page (true or false)"
-// @Param        isMobile  query     bool    false  "Filter by is_mobile (true or false)"
+// @Param        search     query     string  false  "Search keyword (matches url, image_path, public_id, title, description, canonical/final url and Open Graph/Twitter tags)"
+// @Param        order_by   query     string  false  "Order by field: created_at, updated_at, url, domain, width or height (default: created_at)"
+// @Param        sort       query     string  false  "Sort direction: asc or desc (default: desc)"
+// @Param        page       query     int     false  "Page number for offset pagination (default: 1)"
+// @Param        limit      query     int     false  "Page size (default: 10, max: 100)"
+// @Param        cursor     query     string  false  "Cursor from a previous response's next_cursor; takes precedence over page"
+// @Param        from       query     string  false  "Only captures created at or after this date (RFC3339 or YYYY-MM-DD)"
+// @Param        to         query     string  false  "Only captures created before this date (RFC3339 or YYYY-MM-DD, a date includes the whole day)"
+// @Param        domain     query     string  false  "Filter by domain, including its subdomains"
+// @Param        format     query     string  false  "Filter by image format"
+// @Param        width      query     int     false  "Filter by viewport width"
+// @Param        height     query     int     false  "Filter by viewport height"
+// @Param        full_page  query     bool    false  "Filter by full_page (true or false)"
+// @Param        is_mobile  query     bool    false  "Filter by is_mobile (true or false)"
+// @Param        status_code  query   int     false  "Filter by the HTTP status code the captured page returned"
+// @Param        batch_id     query   string  false  "Only captures from this multi-viewport batch"
 // @Success 200 {object} response.Response{data=dto.PagesCaptureResponse} "Successfully get Page Capture"
 // @Failure 400 {object} response.ErrorResponse "invalid request"
 // @Failure 401 {object} response.ErrorResponse "unauthorized"
@@ -89,35 +184,396 @@ func (h *PageCaptureHandler) GetPageCapture(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	search := c.DefaultQuery("search", "")
-	orderBy := c.DefaultQuery("order_by", "created_at")
-	sort := c.DefaultQuery("sort", "desc")
+	query, err := parsePageCaptureQuery(c)
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	pageCapture, err := h.pageCapture.GetPageCapture(&user, query, c.Request.Context())
+	if err != nil {
+		if util.ErrorInList(err, errorEntity.ErrUserNotFound) {
+			response.Unauthorized(c, "unauthorized", err)
+			return
+		} else if util.ErrorInList(err, errorEntity.ErrInvalidRequest) {
+			response.BadRequest(c, "invalid request", err)
+			return
+		}
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully get page capture", pageCapture)
+}
+
+func parsePageCaptureQuery(c *gin.Context) (*dto.PageCaptureQuery, error) {
+	query := &dto.PageCaptureQuery{
+		Search:  c.DefaultQuery("search", ""),
+		OrderBy: c.DefaultQuery("order_by", "created_at"),
+		Sort:    c.DefaultQuery("sort", "desc"),
+		Cursor:  c.Query("cursor"),
+		Domain:  c.Query("domain"),
+		Format:  c.Query("format"),
+	}
+
+	var err error
+	if query.Page, err = strconv.Atoi(c.DefaultQuery("page", "1")); err != nil {
+		return nil, fmt.Errorf("invalid page parameter")
+	}
+
+	if query.Limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(dto.DefaultPageCaptureLimit))); err != nil {
+		return nil, fmt.Errorf("invalid limit parameter")
+	}
+
+	if query.From, err = queryTime(c, "from", false); err != nil {
+		return nil, err
+	}
+
+	if query.To, err = queryTime(c, "to", true); err != nil {
+		return nil, err
+	}
+
+	if query.Width, err = queryInt(c, "width"); err != nil {
+		return nil, err
+	}
+
+	if query.Height, err = queryInt(c, "height"); err != nil {
+		return nil, err
+	}
+
+	if query.StatusCode, err = queryInt(c, "status_code"); err != nil {
+		return nil, err
+	}
+
+	if val, ok := c.GetQuery("batch_id"); ok {
+		batchID, err := uuid.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch_id parameter")
+		}
+		query.BatchID = &batchID
+	}
 
-	var fullPage *bool
 	if val, ok := c.GetQuery("full_page"); ok {
 		b := val == "true"
-		fullPage = &b
+		query.FullPage = &b
 	}
 
-	var isMobile *bool
 	if val, ok := c.GetQuery("is_mobile"); ok {
 		b := val == "true"
-		isMobile = &b
+		query.IsMobile = &b
+	}
+
+	return query, nil
+}
+
+// queryTime parses an RFC3339 timestamp or a YYYY-MM-DD date. When endOfDay is
+// set, a bare date is moved to the start of the following day so that it can
+// be used as an exclusive upper bound.
+func queryTime(c *gin.Context, key string, endOfDay bool) (*time.Time, error) {
+	val, ok := c.GetQuery(key)
+	if !ok || val == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		return &t, nil
 	}
 
-	pageCapture, err := h.pageCapture.GetPageCapture(&user, search, orderBy, sort, page, fullPage, isMobile)
+	t, err := time.Parse(time.DateOnly, val)
 	if err != nil {
-		if util.ErrorInList(err, errorEntity.ErrUserNotFound) {
-			response.Unauthorized(c, "unauthorized", err)
-			return
+		return nil, fmt.Errorf("invalid %s parameter, expected RFC3339 or YYYY-MM-DD", key)
+	}
+
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+func queryInt(c *gin.Context, key string) (*int, error) {
+	val, ok := c.GetQuery(key)
+	if !ok || val == "" {
+		return nil, nil
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s parameter", key)
+	}
+	return &i, nil
+}
+
+// GetPageCaptureDetail godoc
+// @Summary      Get Page Capture Detail
+// @Description  Get a single page capture owned by the authenticated user, including the extracted page metadata
+// @Tags         Page Capture
+// @Produce      json
+// @Param        id  path  string  true  "Page Capture ID"
+// @Success 200 {object} response.Response{data=entity.PageCapture} "Successfully get Page Capture"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /page-capture/{id} [get]
+func (h *PageCaptureHandler) GetPageCaptureDetail(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	pageCapture, err := h.pageCapture.GetPageCaptureDetail(&user, c.Param("id"), c.Request.Context())
+	if err != nil {
+		if util.ErrorInList(err, errorEntity.ErrInvalidRequest) {
+			response.BadRequest(c, "invalid request", err)
 		} else if util.ErrorInList(err, errorEntity.ErrDataNotFound) {
 			response.NotFound(c, "data not found", err)
-			return
+		} else {
+			response.InternalServerError(c, err)
 		}
-		response.InternalServerError(c, err)
 		return
 	}
 
 	response.OK(c, "successfully get page capture", pageCapture)
 }
+
+// GetPageCaptureImage godoc
+// @Summary      Get Page Capture Image
+// @Description  Stream a stored page capture owned by the authenticated user
+// @Tags         Page Capture
+// @Produce      octet-stream
+// @Param        id  path  string  true  "Page Capture ID"
+// @Success 200 {file} binary "Successfully get Page Capture image"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 409 {object} response.ErrorResponse "page capture is still pending upload or failed"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /page-capture/{id}/image [get]
+func (h *PageCaptureHandler) GetPageCaptureImage(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	file, err := h.pageCapture.GetPageCaptureImage(&user, c.Param("id"), c.Request.Context())
+	if err != nil {
+		h.handleFileError(c, err)
+		return
+	}
+
+	streamFile(c, file)
+}
+
+// GetPageCaptureArtifact godoc
+// @Summary      Get Page Capture Artifact
+// @Description  Download an archived snapshot (HTML, MHTML or text) stored with a page capture owned by the authenticated user
+// @Tags         Page Capture
+// @Produce      octet-stream
+// @Param        id          path  string  true  "Page Capture ID"
+// @Param        artifactId  path  string  true  "Artifact ID"
+// @Success 200 {file} binary "Successfully get Page Capture artifact"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 409 {object} response.ErrorResponse "artifact is still pending upload or failed"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /page-capture/{id}/artifacts/{artifactId} [get]
+func (h *PageCaptureHandler) GetPageCaptureArtifact(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	file, err := h.pageCapture.GetPageCaptureArtifact(&user, c.Param("id"), c.Param("artifactId"), c.Request.Context())
+	if err != nil {
+		h.handleFileError(c, err)
+		return
+	}
+
+	downloadFile(c, file)
+}
+
+// CreateSignedUrl godoc
+// @Summary      Create Signed Page Capture URL
+// @Description  Mint an expiring, HMAC-signed URL that serves a page capture without authentication
+// @Tags         Page Capture
+// @Produce      json
+// @Param        id         path   string  true   "Page Capture ID"
+// @Param        expires_in  query  int     false  "Seconds until the URL expires (default: 3600, max: 604800)"
+// @Success 200 {object} response.Response{data=dto.SignedUrlResponse} "Successfully create signed url"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Failure 501 {object} response.ErrorResponse "signing_not_configured"
+// @Security BearerAuth
+// @Router       /page-capture/{id}/signed-url [get]
+func (h *PageCaptureHandler) CreateSignedUrl(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	expiresIn, err := strconv.Atoi(c.DefaultQuery("expires_in", "3600"))
+	if err != nil || expiresIn <= 0 || expiresIn > 604800 {
+		response.BadRequest(c, "invalid request", fmt.Errorf("expires_in must be between 1 and 604800 seconds"))
+		return
+	}
+
+	signed, err := h.pageCapture.CreateSignedUrl(&user, c.Param("id"), expiresIn, c.Request.Context())
+	if err != nil {
+		if util.ErrorInList(err, errorEntity.ErrInvalidRequest) {
+			response.BadRequest(c, "invalid request", err)
+		} else if util.ErrorInList(err, errorEntity.ErrDataNotFound) {
+			response.NotFound(c, "data not found", err)
+		} else if !respondCaptureError(c, err) {
+			response.InternalServerError(c, err)
+		}
+		return
+	}
+
+	response.OK(c, "successfully create signed url", signed)
+}
+
+// GetSharedPageCaptureImage godoc
+// @Summary      Get Shared Page Capture Image
+// @Description  Stream a page capture through a signed URL created by the signed-url endpoint
+// @Tags         Page Capture
+// @Produce      octet-stream
+// @Param        id         path   string  true  "Page Capture ID"
+// @Param        expires    query  int     true  "Expiry as a unix timestamp"
+// @Param        signature  query  string  true  "URL signature"
+// @Success 200 {file} binary "Successfully get Page Capture image"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 403 {object} response.ErrorResponse "forbidden"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Failure 501 {object} response.ErrorResponse "signing_not_configured"
+// @Router       /page-capture/{id}/shared [get]
+func (h *PageCaptureHandler) GetSharedPageCaptureImage(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid request", fmt.Errorf("invalid expires parameter"))
+		return
+	}
+
+	signature := c.Query("signature")
+	if signature == "" {
+		response.BadRequest(c, "missing required query parameter 'signature'", nil)
+		return
+	}
+
+	file, err := h.pageCapture.GetSharedPageCaptureImage(c.Param("id"), expires, signature, c.Request.Context())
+	if err != nil {
+		h.handleFileError(c, err)
+		return
+	}
+
+	streamFile(c, file)
+}
+
+// DeletePageCapture godoc
+// @Summary      Delete Page Capture
+// @Description  Delete a page capture and its stored image
+// @Tags         Page Capture
+// @Produce      json
+// @Param        id  path  string  true  "Page Capture ID"
+// @Success 200 {object} response.Response "Successfully delete page capture"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /page-capture/{id} [delete]
+func (h *PageCaptureHandler) DeletePageCapture(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if err := h.pageCapture.DeletePageCapture(&user, c.Param("id"), c.Request.Context()); err != nil {
+		h.handleFileError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully delete page capture", nil)
+}
+
+// DeletePageCaptures godoc
+// @Summary      Bulk Delete Page Captures
+// @Description  Delete up to 100 page captures and their stored images. IDs that do not exist or belong to another user are ignored.
+// @Tags         Page Capture
+// @Accept       json
+// @Produce      json
+// @Param        request  body  dto.DeletePageCapturesRequest  true  "Page Capture IDs"
+// @Success 200 {object} response.Response{data=dto.DeletePageCapturesResponse} "Successfully delete page captures"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /page-capture [delete]
+func (h *PageCaptureHandler) DeletePageCaptures(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	body, err := util.GetBody[dto.DeletePageCapturesRequest](c, "body")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	result, err := h.pageCapture.DeletePageCaptures(&user, &body, c.Request.Context())
+	if err != nil {
+		h.handleFileError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully delete page captures", result)
+}
+
+func (h *PageCaptureHandler) handleFileError(c *gin.Context, err error) {
+	if util.ErrorInList(err, errorEntity.ErrInvalidRequest) {
+		response.BadRequest(c, "invalid request", err)
+	} else if util.ErrorInList(err, errorEntity.ErrInvalidSignature, errorEntity.ErrSignatureExpired) {
+		response.Forbidden(c, "forbidden", err)
+	} else if util.ErrorInList(err, errorEntity.ErrDataNotFound) {
+		response.NotFound(c, "data not found", err)
+	} else if util.ErrorInList(err, errorEntity.ErrCaptureNotStored) {
+		response.Conflict(c, "page capture is not available", err)
+	} else if !respondCaptureError(c, err) {
+		response.InternalServerError(c, err)
+	}
+}
+
+func streamFile(c *gin.Context, file *dto.PageCaptureFile) {
+	defer file.Content.Close()
+
+	c.DataFromReader(http.StatusOK, -1, file.ContentType, file.Content, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`inline; filename="%s"`, file.Filename),
+		"Cache-Control":       "private, no-store",
+	})
+}
+
+// downloadFile serves archived documents as attachments in a sandbox, so
+// captured HTML never runs scripts on the API's origin.
+func downloadFile(c *gin.Context, file *dto.PageCaptureFile) {
+	defer file.Content.Close()
+
+	c.DataFromReader(http.StatusOK, -1, file.ContentType, file.Content, map[string]string{
+		"Content-Disposition":     fmt.Sprintf(`attachment; filename="%s"`, file.Filename),
+		"Cache-Control":           "private, no-store",
+		"Content-Security-Policy": "sandbox",
+		"X-Content-Type-Options":  "nosniff",
+	})
+}
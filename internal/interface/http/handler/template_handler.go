@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/application/usecase"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/util"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	template *usecase.TemplateUseCase
+}
+
+func NewTemplateHandler(template *usecase.TemplateUseCase) *TemplateHandler {
+	return &TemplateHandler{
+		template: template,
+	}
+}
+
+// CreateTemplate godoc
+// @Summary      Create Template
+// @Description  Save an HTML/CSS template with html/template placeholders such as {{.title}}
+// @Tags         Template
+// @Accept       json
+// @Produce      json
+// @Param        request  body  dto.CreateTemplateRequest  true  "Create Template Request"
+// @Success 201 {object} response.Response{data=entity.Template} "Successfully create template"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	body, err := util.GetBody[dto.CreateTemplateRequest](c, "body")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	tmpl, err := h.template.CreateTemplate(&user, &body, c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Created(c, "successfully create template", tmpl)
+}
+
+// GetTemplates godoc
+// @Summary      Get Templates
+// @Description  List the authenticated user's templates
+// @Tags         Template
+// @Produce      json
+// @Success 200 {object} response.Response{data=[]entity.Template} "Successfully get templates"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /templates [get]
+func (h *TemplateHandler) GetTemplates(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	templates, err := h.template.GetTemplates(&user, c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully get templates", templates)
+}
+
+// GetTemplate godoc
+// @Summary      Get Template
+// @Description  Get a template owned by the authenticated user
+// @Tags         Template
+// @Produce      json
+// @Param        id  path  string  true  "Template ID"
+// @Success 200 {object} response.Response{data=entity.Template} "Successfully get template"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	tmpl, err := h.template.GetTemplate(&user, c.Param("id"), c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully get template", tmpl)
+}
+
+// UpdateTemplate godoc
+// @Summary      Update Template
+// @Description  Update a template. Changing its content or size bumps the version, which invalidates cached renders.
+// @Tags         Template
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                     true  "Template ID"
+// @Param        request  body  dto.UpdateTemplateRequest  true  "Update Template Request"
+// @Success 200 {object} response.Response{data=entity.Template} "Successfully update template"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /templates/{id} [patch]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	body, err := util.GetBody[dto.UpdateTemplateRequest](c, "body")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	tmpl, err := h.template.UpdateTemplate(&user, c.Param("id"), &body, c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully update template", tmpl)
+}
+
+// DeleteTemplate godoc
+// @Summary      Delete Template
+// @Description  Delete a template owned by the authenticated user
+// @Tags         Template
+// @Produce      json
+// @Param        id  path  string  true  "Template ID"
+// @Success 200 {object} response.Response "Successfully delete template"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if err := h.template.DeleteTemplate(&user, c.Param("id"), c.Request.Context()); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully delete template", nil)
+}
+
+// RenderTemplate godoc
+// @Summary      Render Template
+// @Description  Fill a template with JSON variables (escaped for their HTML context) and render it as PNG or PDF. Results are cached per template version and variables.
+// @Tags         Template
+// @Accept       json
+// @Produce      image/png
+// @Produce      application/pdf
+// @Param        id       path  string                     true  "Template ID"
+// @Param        request  body  dto.RenderTemplateRequest  true  "Render Template Request"
+// @Success 200 {file} binary "Successfully render template"
+// @Header  200 {string} X-Cache "HIT when served from the render cache, MISS otherwise"
+// @Failure 400 {object} response.ErrorResponse "invalid request or missing variable"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 404 {object} response.ErrorResponse "data not found"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Failure 503 {object} response.ErrorResponse "browser_unavailable"
+// @Failure 504 {object} response.ErrorResponse "timeout"
+// @Security BearerAuth
+// @Router       /templates/{id}/render [post]
+func (h *TemplateHandler) RenderTemplate(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	body, err := util.GetBody[dto.RenderTemplateRequest](c, "body")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	data, err := h.template.RenderTemplate(&user, c.Param("id"), &body, c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if data.CacheHit {
+		c.Header("X-Cache", "HIT")
+	} else {
+		c.Header("X-Cache", "MISS")
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, data.Filename))
+	c.Data(http.StatusOK, data.ContentType, data.Content)
+}
+
+func (h *TemplateHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case util.ErrorInList(err, errorEntity.ErrInvalidRequest):
+		response.BadRequest(c, "invalid request", err)
+	case util.ErrorInList(err, errorEntity.ErrDataNotFound):
+		response.NotFound(c, "data not found", err)
+	case respondCaptureError(c, err):
+	default:
+		response.InternalServerError(c, err)
+	}
+}
@@ -158,3 +158,37 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 
 	response.OK(c, "successfully delete user account", nil)
 }
+
+// UpdateRetention godoc
+// @Summary      Update Retention Policy
+// @Description  Set how long page captures are kept. Captures older than retention_days or beyond the newest retention_max_captures are removed by a background sweeper.
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        request  body  dto.UpdateRetentionRequest  true  "Update Retention Request"
+// @Success 200 {object} response.Response{data=dto.UserResponse} "Successfully update retention policy"
+// @Failure 400 {object} response.ErrorResponse "invalid request"
+// @Failure 401 {object} response.ErrorResponse "unauthorized"
+// @Failure 500 {object} response.ErrorResponse "internal server error"
+// @Security BearerAuth
+// @Router       /user/retention [patch]
+func (h *UserHandler) UpdateRetention(c *gin.Context) {
+	user, err := util.GetBody[entity.User](c, "user")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	body, err := util.GetBody[dto.UpdateRetentionRequest](c, "body")
+	if err != nil {
+		response.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if err := h.user.UpdateRetention(&body, &user, c.Request.Context()); err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, "successfully update retention policy", dto.ToUserResponse(user))
+}
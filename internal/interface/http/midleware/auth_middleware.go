@@ -3,6 +3,7 @@ package midleware
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/jwt"
@@ -10,6 +11,7 @@ import (
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/contract/repository"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/entity"
 	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	entity2 "github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/entity"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/shared/util"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
@@ -36,9 +38,10 @@ func NewAuthMiddleware(user repository.UserRepository, redis redis.Service, jwt
 
 func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		log := logging.FromContext(c.Request.Context())
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			logrus.Warn("no auth header")
+			log.Warn("no auth header")
 			response.Unauthorized(c, "unauthorized", errorEntity.ErrAuthHeaderNotFound)
 			c.Abort()
 			return
@@ -46,7 +49,7 @@ func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 
 		parts := strings.Split(authHeader, "Bearer ")
 		if len(parts) != 2 {
-			logrus.Warn("invalid auth header")
+			log.Warn("invalid auth header")
 			response.Unauthorized(c, "unauthorized", errorEntity.ErrTokenNotFound)
 			c.Abort()
 			return
@@ -55,7 +58,7 @@ func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 		token := strings.TrimSpace(parts[1])
 
 		if existingToken, err := m.redis.Get(fmt.Sprintf("blacklist:%s", token)); err == nil && existingToken != "" {
-			logrus.Warn("token is blacklisted")
+			log.Warn("token is blacklisted")
 			response.Unauthorized(c, "unauthorized", errorEntity.ErrTokenAlreadyBlacklisted)
 			c.Abort()
 			return
@@ -64,12 +67,12 @@ func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 		claims, err := m.jwt.ValidateToken(token, m.cfg.Jwt.AccessTokenSecret)
 		if err != nil {
 			if util.ErrorInList(err, errorEntity.ErrTokenExpired, errorEntity.ErrInvalidToken) {
-				logrus.Warn("invalid token: ", err)
+				log.Warn("invalid token: ", err)
 				response.Unauthorized(c, "unauthorized", err)
 				c.Abort()
 				return
 			} else {
-				logrus.Error("failed to validate token: ", err)
+				log.Error("failed to validate token: ", err)
 				response.InternalServerError(c, err)
 				c.Abort()
 				return
@@ -82,6 +85,7 @@ func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 			if err := json.Unmarshal([]byte(cachedUser), &user); err == nil {
 				c.Set("accessToken", token)
 				c.Set("user", &user)
+				logging.AddFields(c.Request.Context(), logrus.Fields{"user_id": user.UUID})
 				c.Next()
 				return
 			}
@@ -94,7 +98,7 @@ func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 		}
 
 		if err := m.user.Find(c, &user); err != nil {
-			logrus.Warn("failed to find user: ", err)
+			log.Warn("failed to find user: ", err)
 			response.Unauthorized(c, "unauthorized", errorEntity.ErrUserNotFound)
 			c.Abort()
 			return
@@ -102,7 +106,7 @@ func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 
 		jsonUser, _ := json.Marshal(user)
 		if err := m.redis.Set(fmt.Sprintf("user:%s", claims.UUID), jsonUser, 0); err != nil {
-			logrus.Error("failed to set user: ", err)
+			log.Error("failed to set user: ", err)
 			response.InternalServerError(c, err)
 			c.Abort()
 			return
@@ -110,6 +114,34 @@ func (m *AuthMiddleware) EnsureAuthenticated() gin.HandlerFunc {
 
 		c.Set("accessToken", &token)
 		c.Set("user", &user)
+		logging.AddFields(c.Request.Context(), logrus.Fields{"user_id": user.UUID})
+
+		c.Next()
+	}
+}
+
+// EnsureAdmin lets through users whose email is listed in ADMIN_EMAILS. It
+// must run after EnsureAuthenticated.
+func (m *AuthMiddleware) EnsureAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		log := logging.FromContext(c.Request.Context())
+		user, err := util.GetBody[entity.User](c, "user")
+		if err != nil {
+			log.Warn("admin check without an authenticated user: ", err)
+			response.Unauthorized(c, "unauthorized", errorEntity.ErrUserNotFound)
+			c.Abort()
+			return
+		}
+
+		isAdmin := slices.ContainsFunc(m.cfg.Server.AdminEmails, func(email string) bool {
+			return strings.EqualFold(email, user.Email)
+		})
+		if !isAdmin {
+			log.Warn("user is not an admin")
+			response.Forbidden(c, "forbidden", errorEntity.ErrAdminRequired)
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
@@ -1,11 +1,12 @@
 package midleware
 
 import (
+	"errors"
 	"fmt"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/sirupsen/logrus"
 	"reflect"
 )
 
@@ -30,7 +31,7 @@ func EnsureJsonValidRequest[T any]() gin.HandlerFunc {
 		body := new(T)
 
 		if err := c.ShouldBindJSON(body); err != nil {
-			logrus.Warning("Failed to bind JSON: ", err)
+			logging.FromContext(c.Request.Context()).Warning("Failed to bind JSON: ", err)
 			response.BadRequest(c, "invalid request", err)
 			c.Abort()
 			return
@@ -44,15 +45,15 @@ func EnsureJsonValidRequest[T any]() gin.HandlerFunc {
 				}
 				errStr += fmt.Sprintf("%s %s", e.Field(), e.Tag())
 			}
-			logrus.Warning("Validation errors: ", errStr)
-			response.BadRequest(c, "invalid request", fmt.Errorf(errStr))
+			logging.FromContext(c.Request.Context()).Warning("Validation errors: ", errStr)
+			response.BadRequest(c, "invalid request", errors.New(errStr))
 			c.Abort()
 			return
 		}
 
 		if custom, ok := any(body).(CustomValidatable); ok {
 			if err := custom.Validate(); err != nil {
-				logrus.Warning("Custom validation failed: ", err)
+				logging.FromContext(c.Request.Context()).Warning("Custom validation failed: ", err)
 				response.BadRequest(c, "invalid request", err)
 				c.Abort()
 				return
@@ -83,7 +84,7 @@ func EnsureMultipartValidRequest[T any]() gin.HandlerFunc {
 				}
 				errStr += fmt.Sprintf("%s %s", e.Field(), e.Tag())
 			}
-			response.BadRequest(c, "invalid request", fmt.Errorf(errStr))
+			response.BadRequest(c, "invalid request", errors.New(errStr))
 			c.Abort()
 			return
 		}
@@ -0,0 +1,14 @@
+package route
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/midleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAdminRoutes(rg *gin.RouterGroup, adminHandler *handler.AdminHandler, mm *midleware.AuthMiddleware) {
+	admin := rg.Group("/admin")
+	{
+		admin.GET("/config", mm.EnsureAuthenticated(), mm.EnsureAdmin(), adminHandler.GetConfig)
+	}
+}
@@ -0,0 +1,15 @@
+package route
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterHealthRoutes mounts the probes at the root, where orchestrators
+// expect them.
+func RegisterHealthRoutes(router *gin.Engine, healthHandler *handler.HealthHandler) {
+	router.GET("/healthz", healthHandler.Liveness)
+	router.HEAD("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+	router.HEAD("/readyz", healthHandler.Readiness)
+}
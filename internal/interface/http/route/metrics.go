@@ -0,0 +1,44 @@
+package route
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"time"
+
+	errorEntity "github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/error"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/metrics"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records request latency by route pattern. Requests that match no
+// route share the "unmatched" label.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsAuth only lets scrapers that send "Authorization: Bearer <token>"
+// through, since the metrics describe traffic and the browser pool.
+func MetricsAuth(token string) gin.HandlerFunc {
+	want := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), want) != 1 {
+			response.Unauthorized(c, "unauthorized", errorEntity.ErrInvalidToken)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
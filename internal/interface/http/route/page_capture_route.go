@@ -11,6 +11,14 @@ func RegisterPageCaptureRoutes(rg *gin.RouterGroup, authHandler *handler.PageCap
 	r := rg.Group("/page-capture")
 	{
 		r.POST("/:key", midleware.EnsureJsonValidRequest[dto.PageCaptureRequest](), authHandler.PageCapture)
+		r.POST("/:key/recording", midleware.EnsureJsonValidRequest[dto.PageRecordingRequest](), authHandler.PageRecording)
 		r.GET("/", mm.EnsureAuthenticated(), authHandler.GetPageCapture)
+		r.GET("/:id", mm.EnsureAuthenticated(), authHandler.GetPageCaptureDetail)
+		r.GET("/:id/image", mm.EnsureAuthenticated(), authHandler.GetPageCaptureImage)
+		r.GET("/:id/artifacts/:artifactId", mm.EnsureAuthenticated(), authHandler.GetPageCaptureArtifact)
+		r.GET("/:id/signed-url", mm.EnsureAuthenticated(), authHandler.CreateSignedUrl)
+		r.GET("/:id/shared", authHandler.GetSharedPageCaptureImage)
+		r.DELETE("/", mm.EnsureAuthenticated(), midleware.EnsureJsonValidRequest[dto.DeletePageCapturesRequest](), authHandler.DeletePageCaptures)
+		r.DELETE("/:id", mm.EnsureAuthenticated(), authHandler.DeletePageCapture)
 	}
 }
@@ -2,15 +2,23 @@ package route
 
 import (
 	"fmt"
+	"runtime/debug"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
 	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/response"
 	"github.com/gin-gonic/gin"
 )
 
 func CustomRecovery() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		if err, ok := recovered.(error); ok {
-			fmt.Printf("Panic recovered: %v\n", err)
-			response.InternalServerError(c, err)
+		err, ok := recovered.(error)
+		if !ok {
+			err = fmt.Errorf("%v", recovered)
 		}
+
+		logging.FromContext(c.Request.Context()).
+			WithField("stack", string(debug.Stack())).
+			Error("panic recovered: ", err)
+		response.InternalServerError(c, err)
 	})
 }
@@ -0,0 +1,36 @@
+package route
+
+import (
+	"regexp"
+
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// requestIDPattern limits propagated IDs to something safe to echo and log.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID propagates the caller's X-Request-ID, or generates one, echoes it
+// in the response and starts the request logger with it. Page capture routes
+// also log the API key prefix.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Header(requestIDHeader, id)
+
+		fields := logrus.Fields{"request_id": id}
+		if key := c.Param("key"); key != "" {
+			fields["api_key_prefix"] = logging.APIKeyPrefix(key)
+		}
+		c.Request = c.Request.WithContext(logging.NewContext(c.Request.Context(), fields))
+
+		c.Next()
+	}
+}
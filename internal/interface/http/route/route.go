@@ -3,48 +3,69 @@ package route
 import (
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
 	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/midleware"
+	"github.com/SyahrulBhudiF/Doc-Management.git/pkg/config"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"net/http"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type Route struct {
+	Config             *config.Config
 	AuthHandler        *handler.AuthHandler
 	AuthMiddleware     *midleware.AuthMiddleware
 	UserHandler        *handler.UserHandler
 	PageCaptureHandler *handler.PageCaptureHandler
+	TemplateHandler    *handler.TemplateHandler
+	HealthHandler      *handler.HealthHandler
+	AdminHandler       *handler.AdminHandler
 }
 
-func NewRoute(authHandler *handler.AuthHandler, middleware *midleware.AuthMiddleware, UserHandler *handler.UserHandler, PageHandler *handler.PageCaptureHandler) *Route {
+func NewRoute(cfg *config.Config, authHandler *handler.AuthHandler, middleware *midleware.AuthMiddleware, UserHandler *handler.UserHandler, PageHandler *handler.PageCaptureHandler, TemplateHandler *handler.TemplateHandler, HealthHandler *handler.HealthHandler, AdminHandler *handler.AdminHandler) *Route {
 	return &Route{
+		Config:             cfg,
 		AuthHandler:        authHandler,
 		AuthMiddleware:     middleware,
 		UserHandler:        UserHandler,
 		PageCaptureHandler: PageHandler,
+		TemplateHandler:    TemplateHandler,
+		HealthHandler:      HealthHandler,
+		AdminHandler:       AdminHandler,
 	}
 }
 
 func (r *Route) RegisterRoutes() *gin.Engine {
-	gin.SetMode(gin.DebugMode)
+	gin.SetMode(r.Config.Server.GinMode)
 
 	router := gin.New()
 
-	router.Use(cors.New(CorsConfig()))
+	router.Use(cors.New(CorsConfig(r.Config.Server.CorsOrigins)))
+	router.Use(RequestID())
+	router.Use(Tracing())
 	router.Use(CustomRecovery())
 	router.Use(Logger())
+	router.Use(Metrics())
 	router.Use(gin.Recovery())
 
+	// Metrics are only served to scrapers holding METRICS_TOKEN.
+	if token := r.Config.Server.MetricsToken; token != "" {
+		router.GET("/metrics", MetricsAuth(token), gin.WrapH(promhttp.Handler()))
+	}
+	RegisterHealthRoutes(router, r.HealthHandler)
+
 	v1 := router.Group("/api/v1")
 	{
-		v1.HEAD("/health", func(c *gin.Context) {
-			c.String(http.StatusOK, "ok")
-		})
+		v1.HEAD("/health", r.HealthHandler.Liveness)
+		v1.GET("/health", r.HealthHandler.Liveness)
 		// Auth
 		RegisterAuthRoutes(v1, r.AuthHandler, r.AuthMiddleware)
 		// User
 		RegisterUserRoutes(v1, r.UserHandler, r.AuthMiddleware)
 		// Page Capture
 		RegisterPageCaptureRoutes(v1, r.PageCaptureHandler, r.AuthMiddleware)
+		// Template
+		RegisterTemplateRoutes(v1, r.TemplateHandler, r.AuthMiddleware)
+		// Admin
+		RegisterAdminRoutes(v1, r.AdminHandler, r.AuthMiddleware)
 	}
 
 	return router
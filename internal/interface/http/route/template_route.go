@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/domain/dto"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/handler"
+	"github.com/SyahrulBhudiF/Doc-Management.git/internal/interface/http/midleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterTemplateRoutes(rg *gin.RouterGroup, templateHandler *handler.TemplateHandler, mm *midleware.AuthMiddleware) {
+	r := rg.Group("/templates")
+	{
+		r.POST("/", mm.EnsureAuthenticated(), midleware.EnsureJsonValidRequest[dto.CreateTemplateRequest](), templateHandler.CreateTemplate)
+		r.GET("/", mm.EnsureAuthenticated(), templateHandler.GetTemplates)
+		r.GET("/:id", mm.EnsureAuthenticated(), templateHandler.GetTemplate)
+		r.PATCH("/:id", mm.EnsureAuthenticated(), midleware.EnsureJsonValidRequest[dto.UpdateTemplateRequest](), templateHandler.UpdateTemplate)
+		r.DELETE("/:id", mm.EnsureAuthenticated(), templateHandler.DeleteTemplate)
+		r.POST("/:id/render", mm.EnsureAuthenticated(), midleware.EnsureJsonValidRequest[dto.RenderTemplateRequest](), templateHandler.RenderTemplate)
+	}
+}
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +17,53 @@ type Config struct {
 	Mail       MailConfig
 	Oauth2     Oauth2Config
 	Cloudinary CloudinaryConfig
+	Retention  RetentionConfig
+	Upload     UploadConfig
+	Browser    BrowserConfig
+	Tracing    TracingConfig
+	Logging    LoggingConfig
+
+	// File is the config file the settings were read from, if any.
+	File     string
+	settings []Setting
+}
+
+type RetentionConfig struct {
+	SweepInterval time.Duration
+}
+
+// BrowserConfig holds the default upstream proxy (an http or socks5 URL), a
+// pool of named proxies requests can pick from, as name=url pairs separated
+// by commas, whether captures get their own incognito context, and the
+// default and maximum capture deadlines. TabLimit caps open tabs, closing the
+// oldest beyond it (0 means no limit), and the browser is restarted after
+// IdleTimeout without captures.
+type BrowserConfig struct {
+	Proxy             string
+	ProxyPool         string
+	Isolation         string
+	CaptureTimeout    time.Duration
+	MaxCaptureTimeout time.Duration
+	Headless          bool
+	TabLimit          int
+	IdleTimeout       time.Duration
+}
+
+// TracingConfig selects the span exporter: none, stdout or otlp.
+type TracingConfig struct {
+	Exporter    string
+	ServiceName string
+}
+
+// LoggingConfig selects the log format, text or json, and the minimum level.
+type LoggingConfig struct {
+	Format string
+	Level  string
+}
+
+type UploadConfig struct {
+	PollInterval time.Duration
+	MaxAttempts  int
 }
 
 type Oauth2Config struct {
@@ -42,29 +92,40 @@ type DatabaseConfig struct {
 	SSLMode     string
 }
 
+// ServerConfig holds, among others, the listen port, gin's debug, release or
+// test mode, the browser origins allowed to call the API with credentials and
+// the emails of the users allowed to use the admin endpoints.
 type ServerConfig struct {
-	Port        string
-	Environment string
-	Salt        string
-	ExpireKey   string
+	Port            int
+	GinMode         string
+	CorsOrigins     []string
+	Environment     string
+	Salt            string
+	ExpireKey       time.Duration
+	PublicUrl       string
+	SignedUrlSecret string
+	ShutdownTimeout time.Duration
+	ReadinessDelay  time.Duration
+	AdminEmails     []string
+	MetricsToken    string
 }
 
 type JwtConfig struct {
 	AccessTokenSecret  string
-	AccessTokenExpire  string
+	AccessTokenExpire  time.Duration
 	RefreshTokenSecret string
-	RefreshTokenExpire string
+	RefreshTokenExpire time.Duration
 }
 
 type RedisConfig struct {
 	Host     string
-	Port     string
+	Port     int
 	Password string
 }
 
 type MailConfig struct {
 	Host        string
-	Port        string
+	Port        int
 	Username    string
 	Password    string
 	MailApiKey  string
@@ -72,63 +133,131 @@ type MailConfig struct {
 	MailAccount string
 }
 
+// Load reads the configuration from the environment, a .env file and the
+// YAML file named by CONFIG_FILE, in that order of precedence. Typed settings
+// are parsed and validated here, along with the settings each enabled
+// feature requires, and every problem is reported in the returned error.
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
-	return &Config{
+	path := os.Getenv("CONFIG_FILE")
+	var file map[string]string
+	if path != "" {
+		var err error
+		if file, err = loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	p := newEnvParser(file)
+	environment := p.string("ENVIRONMENT", "")
+	// gin also reads GIN_MODE when it is imported and panics on unknown
+	// modes, so its check below mostly guards the default and the file.
+	defaultGinMode := "debug"
+	if environment == "production" {
+		defaultGinMode = "release"
+	}
+
+	cfg := &Config{
+		File: path,
 		Database: DatabaseConfig{
-			DatabaseUrl: getEnv("DATABASE_URL", ""),
-			Host:        getEnv("DB_HOST", ""),
-			Port:        getEnv("DB_PORT", ""),
-			User:        getEnv("DB_USER", ""),
-			Password:    getEnv("DB_PASSWORD", ""),
-			DbName:      getEnv("DB_NAME", ""),
-			SSLMode:     getEnv("DB_SSL_MODE", ""),
+			DatabaseUrl: p.dsn("DATABASE_URL"),
+			Host:        p.string("DB_HOST", ""),
+			Port:        p.string("DB_PORT", ""),
+			User:        p.string("DB_USER", ""),
+			Password:    p.secret("DB_PASSWORD"),
+			DbName:      p.string("DB_NAME", ""),
+			SSLMode:     p.string("DB_SSL_MODE", ""),
 		},
 		Server: ServerConfig{
-			Port:        getEnv("SERVER_PORT", ""),
-			Environment: getEnv("ENVIRONMENT", ""),
-			Salt:        getEnv("SALT", ""),
-			ExpireKey:   getEnv("KEY_EXPIRATION", ""),
+			Port:            p.int("SERVER_PORT", 8080, 1, 65535),
+			GinMode:         p.oneOf("GIN_MODE", defaultGinMode, "debug", "release", "test"),
+			CorsOrigins:     p.origins("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
+			Environment:     environment,
+			Salt:            p.secret("SALT"),
+			ExpireKey:       p.duration("KEY_EXPIRATION", 60*time.Minute),
+			PublicUrl:       p.url("PUBLIC_URL", ""),
+			SignedUrlSecret: p.secret("SIGNED_URL_SECRET"),
+			ShutdownTimeout: p.duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+			ReadinessDelay:  p.delay("SHUTDOWN_READINESS_DELAY", 5*time.Second),
+			AdminEmails:     p.list("ADMIN_EMAILS", nil),
+			MetricsToken:    p.secret("METRICS_TOKEN"),
 		},
 		Jwt: JwtConfig{
-			AccessTokenSecret:  getEnv("ACCESS_TOKEN_SECRET", ""),
-			AccessTokenExpire:  getEnv("ACCESS_TOKEN_EXPIRE", ""),
-			RefreshTokenSecret: getEnv("REFRESH_TOKEN_SECRET", ""),
-			RefreshTokenExpire: getEnv("REFRESH_TOKEN_EXPIRE", ""),
+			AccessTokenSecret:  p.secret("ACCESS_TOKEN_SECRET"),
+			AccessTokenExpire:  p.duration("ACCESS_TOKEN_EXPIRE", 60*time.Minute),
+			RefreshTokenSecret: p.secret("REFRESH_TOKEN_SECRET"),
+			RefreshTokenExpire: p.duration("REFRESH_TOKEN_EXPIRE", 168*time.Hour),
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", ""),
-			Port:     getEnv("REDIS_PORT", ""),
-			Password: getEnv("REDIS_PASSWORD", ""),
+			Host:     p.string("REDIS_HOST", ""),
+			Port:     p.int("REDIS_PORT", 6379, 1, 65535),
+			Password: p.secret("REDIS_PASSWORD"),
 		},
 		Mail: MailConfig{
-			Host:        getEnv("MAIL_HOST", ""),
-			Port:        getEnv("MAIL_PORT", ""),
-			Username:    getEnv("MAIL_USERNAME", ""),
-			Password:    getEnv("MAIL_PASSWORD", ""),
-			MailApiKey:  getEnv("MAIL_API", ""),
-			MailInboxId: getEnv("MAIL_INBOX_ID", ""),
-			MailAccount: getEnv("MAIL_ACCOUNT", ""),
+			Host:        p.string("MAIL_HOST", ""),
+			Port:        p.int("MAIL_PORT", 587, 1, 65535),
+			Username:    p.string("MAIL_USERNAME", ""),
+			Password:    p.secret("MAIL_PASSWORD"),
+			MailApiKey:  p.secret("MAIL_API"),
+			MailInboxId: p.string("MAIL_INBOX_ID", ""),
+			MailAccount: p.string("MAIL_ACCOUNT", ""),
 		},
 		Oauth2: Oauth2Config{
 			Google: GoogleConfig{
-				ClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
-				ClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
-				RedirectURL:  getEnv("GOOGLE_REDIRECT_URL", ""),
+				ClientID:     p.string("GOOGLE_CLIENT_ID", ""),
+				ClientSecret: p.secret("GOOGLE_CLIENT_SECRET"),
+				RedirectURL:  p.url("GOOGLE_REDIRECT_URL", ""),
 			},
 		},
 		Cloudinary: CloudinaryConfig{
-			CloudName: getEnv("CLOUDINARY_CLOUD_NAME", ""),
-			ApiKey:    getEnv("CLOUDINARY_API_KEY", ""),
-			ApiSecret: getEnv("CLOUDINARY_API_SECRET", ""),
+			CloudName: p.string("CLOUDINARY_CLOUD_NAME", ""),
+			ApiKey:    p.string("CLOUDINARY_API_KEY", ""),
+			ApiSecret: p.secret("CLOUDINARY_API_SECRET"),
 		},
-	}, nil
-}
+		Retention: RetentionConfig{
+			SweepInterval: p.duration("RETENTION_SWEEP_INTERVAL", time.Hour),
+		},
+		Upload: UploadConfig{
+			PollInterval: p.duration("UPLOAD_POLL_INTERVAL", 5*time.Second),
+			MaxAttempts:  p.int("UPLOAD_MAX_ATTEMPTS", 8, 1, 100),
+		},
+		Browser: BrowserConfig{
+			Proxy:     p.proxies("BROWSER_PROXY"),
+			ProxyPool: p.proxies("PROXY_POOL"),
+			Isolation: p.oneOf("BROWSER_ISOLATION", "incognito", "incognito", "shared"),
+
+			CaptureTimeout:    p.duration("CAPTURE_TIMEOUT", 30*time.Second),
+			MaxCaptureTimeout: p.duration("CAPTURE_MAX_TIMEOUT", 2*time.Minute),
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+			Headless:    p.bool("BROWSER_HEADLESS", true),
+			TabLimit:    p.int("BROWSER_TAB_LIMIT", 5, 0, 100),
+			IdleTimeout: p.duration("BROWSER_IDLE_TIMEOUT", 2*time.Minute),
+		},
+		Tracing: TracingConfig{
+			Exporter:    p.oneOf("TRACING_EXPORTER", "none", "none", "stdout", "otlp"),
+			ServiceName: p.string("OTEL_SERVICE_NAME", "pagecap-api"),
+		},
+		Logging: LoggingConfig{
+			Format: p.oneOf("LOG_FORMAT", "text", "text", "json"),
+			Level:  p.oneOf("LOG_LEVEL", "info", "trace", "debug", "info", "warn", "warning", "error", "fatal", "panic"),
+		},
+	}
+
+	validate(p, cfg)
+	for _, key := range p.unknownKeys() {
+		p.errs = append(p.errs, fmt.Errorf("%s: unknown setting in %s", key, path))
 	}
-	return defaultValue
+
+	if err := errors.Join(p.errs...); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	cfg.settings = p.settings
+	return cfg, nil
+}
+
+// Settings returns every setting's effective value, with secrets redacted,
+// and where it came from.
+func (c *Config) Settings() []Setting {
+	return c.settings
 }
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadFile reads a YAML config file into settings keyed like the environment
+// variables. Nested keys are joined with underscores and upper-cased, so
+//
+//	browser:
+//	  tab_limit: 5
+//
+// sets BROWSER_TAB_LIMIT, and lists are joined with commas.
+func loadFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	settings := map[string]string{}
+	if err := flatten(settings, "", doc); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+func flatten(settings map[string]string, prefix string, value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			name := strings.ToUpper(key)
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			if err := flatten(settings, name, child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, nested := item.(map[string]any); nested {
+				return fmt.Errorf("%s: lists may only hold plain values", prefix)
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		return set(settings, prefix, strings.Join(items, ","))
+	case map[any]any:
+		return fmt.Errorf("%s: keys must be strings", prefix)
+	case nil:
+		return set(settings, prefix, "")
+	default:
+		return set(settings, prefix, fmt.Sprint(v))
+	}
+	return nil
+}
+
+func set(settings map[string]string, key string, value string) error {
+	if _, exists := settings[key]; exists {
+		return fmt.Errorf("%s is set more than once", key)
+	}
+	settings[key] = value
+	return nil
+}
+
+// unknownKeys returns the file settings the parser never read, which are
+// most likely typos.
+func (p *envParser) unknownKeys() []string {
+	var unknown []string
+	for key := range p.file {
+		if !p.known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
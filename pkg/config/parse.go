@@ -0,0 +1,278 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sources a setting's effective value can come from.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// Setting is one configuration value as it was resolved at startup. Secrets
+// are redacted in Value.
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// envParser reads typed settings from the environment, falling back to the
+// config file, and collects every invalid value instead of stopping at the
+// first, so startup reports them all at once. It also records what each
+// setting resolved to and where it came from.
+type envParser struct {
+	file     map[string]string
+	errs     []error
+	settings []Setting
+	known    map[string]bool
+}
+
+func newEnvParser(file map[string]string) *envParser {
+	return &envParser{file: file, known: map[string]bool{}}
+}
+
+func (p *envParser) fail(key string, value string, reason string) {
+	p.errs = append(p.errs, fmt.Errorf("%s=%q: %s", key, value, reason))
+}
+
+// lookup returns the raw value of key and its source. The environment,
+// including a .env file, wins over the config file.
+func (p *envParser) lookup(key string) (string, string) {
+	p.known[key] = true
+	if value := os.Getenv(key); value != "" {
+		return value, SourceEnv
+	}
+	if value := p.file[key]; value != "" {
+		return value, SourceFile
+	}
+	return "", SourceDefault
+}
+
+func (p *envParser) record(key string, value string, source string) {
+	p.settings = append(p.settings, Setting{Key: key, Value: value, Source: source})
+}
+
+func (p *envParser) string(key string, defaultValue string) string {
+	raw, source := p.lookup(key)
+	if raw == "" {
+		raw = defaultValue
+	}
+	p.record(key, raw, source)
+	return raw
+}
+
+// secret is string for credentials, which are never shown in Settings.
+func (p *envParser) secret(key string) string {
+	raw, source := p.lookup(key)
+	p.record(key, redactSecret(raw), source)
+	return raw
+}
+
+// url reads an http(s) URL. A password in its userinfo is redacted in
+// Settings.
+func (p *envParser) url(key string, defaultValue string) string {
+	raw, source := p.lookup(key)
+	if raw == "" {
+		p.record(key, defaultValue, source)
+		return defaultValue
+	}
+
+	p.record(key, redactURL(raw), source)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		p.fail(key, redactURL(raw), "must be an http or https URL")
+		return defaultValue
+	}
+	return raw
+}
+
+func (p *envParser) int(key string, defaultValue int, min int, max int) int {
+	raw, source := p.lookup(key)
+	if raw == "" {
+		p.record(key, strconv.Itoa(defaultValue), source)
+		return defaultValue
+	}
+
+	p.record(key, raw, source)
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		p.fail(key, raw, "must be an integer")
+		return defaultValue
+	}
+	if value < min || value > max {
+		p.fail(key, raw, fmt.Sprintf("must be between %d and %d", min, max))
+		return defaultValue
+	}
+	return value
+}
+
+func (p *envParser) bool(key string, defaultValue bool) bool {
+	raw, source := p.lookup(key)
+	if raw == "" {
+		p.record(key, strconv.FormatBool(defaultValue), source)
+		return defaultValue
+	}
+
+	p.record(key, raw, source)
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.fail(key, raw, "must be true or false")
+		return defaultValue
+	}
+	return value
+}
+
+// duration parses a positive Go duration such as 30s or 2m.
+func (p *envParser) duration(key string, defaultValue time.Duration) time.Duration {
+	return p.parseDuration(key, defaultValue, false)
+}
+
+// delay is duration for waits that 0 turns off.
+func (p *envParser) delay(key string, defaultValue time.Duration) time.Duration {
+	return p.parseDuration(key, defaultValue, true)
+}
+
+func (p *envParser) parseDuration(key string, defaultValue time.Duration, allowZero bool) time.Duration {
+	raw, source := p.lookup(key)
+	if raw == "" {
+		p.record(key, defaultValue.String(), source)
+		return defaultValue
+	}
+
+	p.record(key, raw, source)
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		p.fail(key, raw, "must be a duration such as 30s or 5m")
+		return defaultValue
+	}
+	if value < 0 {
+		p.fail(key, raw, "must not be negative")
+		return defaultValue
+	}
+	if value == 0 && !allowZero {
+		p.fail(key, raw, "must be positive")
+		return defaultValue
+	}
+	return value
+}
+
+func (p *envParser) oneOf(key string, defaultValue string, allowed ...string) string {
+	raw, source := p.lookup(key)
+	if raw == "" {
+		p.record(key, defaultValue, source)
+		return defaultValue
+	}
+
+	p.record(key, raw, source)
+	if !slices.Contains(allowed, raw) {
+		p.fail(key, raw, "must be one of "+strings.Join(allowed, ", "))
+		return defaultValue
+	}
+	return raw
+}
+
+// list parses a comma separated list, dropping empty entries.
+func (p *envParser) list(key string, defaultValue []string) []string {
+	raw, source := p.lookup(key)
+	if raw == "" {
+		p.record(key, strings.Join(defaultValue, ","), source)
+		return defaultValue
+	}
+
+	p.record(key, raw, source)
+	return splitList(raw)
+}
+
+// origins parses a list of CORS origins. Credentials are allowed, so
+// wildcards are rejected and each entry must be a bare http(s) origin.
+func (p *envParser) origins(key string, defaultValue []string) []string {
+	origins := p.list(key, defaultValue)
+	if len(origins) == 0 {
+		p.fail(key, "", "must list at least one origin")
+		return defaultValue
+	}
+
+	for _, origin := range origins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Contains(origin, "*") || strings.TrimSuffix(u.Path, "/") != "" {
+			p.fail(key, origin, "origins must look like https://app.example.com, without wildcards or paths")
+			return defaultValue
+		}
+	}
+	return origins
+}
+
+// dsn reads a database connection string. A URL has its password redacted
+// in Settings, and a key=value DSN is hidden entirely.
+func (p *envParser) dsn(key string) string {
+	raw, source := p.lookup(key)
+	shown := redactURL(raw)
+	if !strings.Contains(raw, "://") {
+		shown = redactSecret(raw)
+	}
+	p.record(key, shown, source)
+	return raw
+}
+
+// proxies reads a proxy URL, or a list of name=url proxies, whose
+// credentials are redacted in Settings. They are parsed by the browser
+// package.
+func (p *envParser) proxies(key string) string {
+	raw, source := p.lookup(key)
+	var redacted []string
+	for _, entry := range splitList(raw) {
+		name, value, ok := strings.Cut(entry, "=")
+		if ok && !strings.Contains(name, "://") {
+			redacted = append(redacted, name+"="+redactURL(value))
+			continue
+		}
+		redacted = append(redacted, redactURL(entry))
+	}
+	p.record(key, strings.Join(redacted, ","), source)
+	return raw
+}
+
+// require reports key as missing when value is empty. feature names what
+// needs it.
+func (p *envParser) require(key string, value string, feature string) {
+	if value == "" {
+		p.errs = append(p.errs, fmt.Errorf("%s: required by %s", key, feature))
+	}
+}
+
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); ok {
+		return u.Redacted()
+	}
+	return raw
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validate checks the settings each enabled feature needs and the ones that
+// only make sense together.
+func validate(p *envParser, cfg *Config) {
+	p.require("DATABASE_URL", cfg.Database.DatabaseUrl, "the database")
+	p.require("REDIS_HOST", cfg.Redis.Host, "the cache and sessions")
+	p.require("SALT", cfg.Server.Salt, "password hashing")
+	p.require("ACCESS_TOKEN_SECRET", cfg.Jwt.AccessTokenSecret, "authentication")
+	p.require("REFRESH_TOKEN_SECRET", cfg.Jwt.RefreshTokenSecret, "authentication")
+	p.require("MAIL_HOST", cfg.Mail.Host, "email verification and password resets")
+	p.require("CLOUDINARY_CLOUD_NAME", cfg.Cloudinary.CloudName, "capture storage")
+	p.require("CLOUDINARY_API_KEY", cfg.Cloudinary.ApiKey, "capture storage")
+	p.require("CLOUDINARY_API_SECRET", cfg.Cloudinary.ApiSecret, "capture storage")
+
+	// A shared secret would let refresh tokens pass as access tokens.
+	if cfg.Jwt.AccessTokenSecret != "" && cfg.Jwt.AccessTokenSecret == cfg.Jwt.RefreshTokenSecret {
+		p.errs = append(p.errs, errors.New("ACCESS_TOKEN_SECRET and REFRESH_TOKEN_SECRET must differ"))
+	}
+
+	// Signed links are built on PUBLIC_URL rather than the request's Host
+	// header, which the client controls.
+	if cfg.Server.SignedUrlSecret != "" {
+		p.require("PUBLIC_URL", cfg.Server.PublicUrl, "signed capture URLs")
+	}
+
+	// Google sign-in is enabled by setting any of its settings.
+	google := cfg.Oauth2.Google
+	if google.ClientID != "" || google.ClientSecret != "" || google.RedirectURL != "" {
+		p.require("GOOGLE_CLIENT_ID", google.ClientID, "Google sign-in")
+		p.require("GOOGLE_CLIENT_SECRET", google.ClientSecret, "Google sign-in")
+		p.require("GOOGLE_REDIRECT_URL", google.RedirectURL, "Google sign-in")
+	}
+
+	if cfg.Browser.CaptureTimeout > cfg.Browser.MaxCaptureTimeout {
+		p.errs = append(p.errs, fmt.Errorf("CAPTURE_TIMEOUT %s exceeds CAPTURE_MAX_TIMEOUT %s", cfg.Browser.CaptureTimeout, cfg.Browser.MaxCaptureTimeout))
+	}
+}
@@ -2,13 +2,19 @@ package response
 
 import (
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 )
 
+// ErrorResponse is the error body. Code is a stable, machine-readable
+// identifier for errors clients may want to branch on, such as whether to
+// retry. TraceID identifies the request's trace for support and debugging.
 type ErrorResponse struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
 	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 type Response struct {
@@ -16,6 +22,8 @@ type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"`
+	TraceID string      `json:"trace_id,omitempty"`
 }
 
 func Success(c *gin.Context, status int, message string, data interface{}) {
@@ -30,6 +38,20 @@ func Error(c *gin.Context, status int, message string, err error) {
 	response := Response{
 		Status:  status,
 		Message: message,
+		TraceID: traceID(c),
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	c.JSON(status, response)
+}
+
+func ErrorWithCode(c *gin.Context, status int, code string, message string, err error) {
+	response := Response{
+		Status:  status,
+		Message: message,
+		Code:    code,
+		TraceID: traceID(c),
 	}
 	if err != nil {
 		response.Error = err.Error()
@@ -68,3 +90,11 @@ func InternalServerError(c *gin.Context, err error) {
 func Conflict(c *gin.Context, message string, err error) {
 	Error(c, http.StatusConflict, message, err)
 }
+
+func traceID(c *gin.Context) string {
+	sc := trace.SpanContextFromContext(c.Request.Context())
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
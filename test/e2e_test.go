@@ -223,6 +223,54 @@ func TestE2E(t *testing.T) {
 		}
 	})
 
+	// --- 6b. Page Capture served from cache ---
+	t.Run("Page Capture Cache", func(t *testing.T) {
+		if apiKey == "" {
+			t.Skip("Skipping Page Capture Cache test as API key was not obtained.")
+		}
+
+		pageCapturePayload := map[string]interface{}{
+			"url":      "https://example.com",
+			"cacheTtl": 60,
+		}
+
+		requestURL := baseURL + "/page-capture/" + apiKey
+		for i, expected := range []string{"MISS", "HIT"} {
+			resp, body, err := makeRequest("POST", requestURL, pageCapturePayload, nil)
+			if err != nil {
+				t.Fatalf("Page Capture request %d failed: %v", i+1, err)
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d. Response: %s", http.StatusOK, resp.StatusCode, string(body))
+			}
+
+			if got := resp.Header.Get("X-Cache"); got != expected {
+				t.Errorf("Request %d: expected X-Cache '%s', got '%s'", i+1, expected, got)
+			}
+		}
+	})
+
+	t.Run("Page Capture Multiple Sources", func(t *testing.T) {
+		if apiKey == "" {
+			t.Skip("Skipping Page Capture Multiple Sources test as API key was not obtained.")
+		}
+
+		pageCapturePayload := map[string]interface{}{
+			"url":  "https://example.com",
+			"html": "<h1>Invoice</h1>",
+		}
+
+		resp, body, err := makeRequest("POST", baseURL+"/page-capture/"+apiKey, pageCapturePayload, nil)
+		if err != nil {
+			t.Fatalf("Page Capture request failed: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d. Response: %s", http.StatusBadRequest, resp.StatusCode, string(body))
+		}
+	})
+
 	// --- 7. Refresh Token ---
 	t.Run("Refresh Token", func(t *testing.T) {
 		if refreshToken == "" {
@@ -327,6 +375,62 @@ func TestE2E(t *testing.T) {
 		}
 	})
 
+	// --- 8a. History rejects sort fields outside the whitelist ---
+	t.Run("Get Page Capture History Invalid Sort", func(t *testing.T) {
+		if accessToken == "" {
+			t.Skip("Skipping History Invalid Sort test as access token was not obtained.")
+		}
+
+		headers := map[string]string{
+			"Authorization": "Bearer " + accessToken,
+		}
+
+		resp, body, err := makeRequest("GET", baseURL+"/page-capture?order_by=user_id;DROP%20TABLE%20users", nil, headers)
+		if err != nil {
+			t.Fatalf("History request failed: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d. Response: %s", http.StatusBadRequest, resp.StatusCode, string(body))
+		}
+	})
+
+	// --- 8b. Bulk delete ignores captures the user does not own ---
+	t.Run("Bulk Delete Unknown Page Captures", func(t *testing.T) {
+		if accessToken == "" {
+			t.Skip("Skipping Bulk Delete test as access token was not obtained.")
+		}
+
+		headers := map[string]string{
+			"Authorization": "Bearer " + accessToken,
+		}
+		payload := map[string]interface{}{
+			"ids": []string{uuid.NewString()},
+		}
+
+		resp, body, err := makeRequest("DELETE", baseURL+"/page-capture", payload, headers)
+		if err != nil {
+			t.Fatalf("Bulk Delete request failed: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d. Response: %s", http.StatusOK, resp.StatusCode, string(body))
+		}
+
+		var deleteResponse struct {
+			Data struct {
+				Deleted int `json:"deleted"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &deleteResponse); err != nil {
+			t.Fatalf("Failed to decode bulk delete response: %v", err)
+		}
+
+		if deleteResponse.Data.Deleted != 0 {
+			t.Errorf("Expected 0 deleted captures, got %d", deleteResponse.Data.Deleted)
+		}
+	})
+
 	// --- 9. Logout ---
 	t.Run("Logout User", func(t *testing.T) {
 		if accessToken == "" {